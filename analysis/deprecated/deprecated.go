@@ -0,0 +1,161 @@
+// Package deprecated defines an Analyzer that reports calls to the legacy,
+// type-specific helpers of gobag and suggests their generic replacements.
+//
+// The legacy helpers and their replacements are:
+//
+//	ArrayContainsStr                         -> SliceContains
+//	SliceExclusionStrings, SliceExclusionInts -> SliceExclusionOrdered
+//	SliceIntersectStrings, SliceIntersectInts -> SliceIntersectionOrdered
+//	SliceUniqInts, SliceUniqInt64s, SliceUniqStrings -> FilterUniqueElements
+//
+// The exclusion and intersection helpers are rewritten to the Ordered
+// variants, which return exactly the same results. SliceExclusion and
+// SliceIntersection may be used instead when the order of the result does not
+// matter.
+//
+// The SliceUniq helpers deduplicate in place, overwriting the backing array of
+// their argument, whereas FilterUniqueElements returns a new slice and leaves
+// its argument untouched. A fix is only suggested when the result of the call
+// is used, since code relying on the mutation alone would silently change
+// behavior.
+//
+// When every argument of a call is an untyped nil, the generic replacement
+// cannot infer its type parameter, so the fix instantiates it explicitly, as
+// in SliceExclusionOrdered[string](nil, nil).
+package deprecated
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const gobagPath = "github.com/neumachen/gobag"
+
+// Analyzer reports calls to the legacy gobag helpers.
+var Analyzer = &analysis.Analyzer{
+	Name:     "gobagdeprecated",
+	Doc:      "report calls to legacy gobag helpers and suggest their generic replacements",
+	URL:      "https://pkg.go.dev/github.com/neumachen/gobag/analysis/deprecated",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+type replacement struct {
+	name string
+	note string
+	// mutatesInPlace marks legacy helpers whose callers may depend on the
+	// mutation of their argument.
+	mutatesInPlace bool
+}
+
+const uniqNote = "; note that FilterUniqueElements returns a new slice instead of deduplicating its argument in place"
+
+var replacements = map[string]replacement{
+	"ArrayContainsStr": {name: "SliceContains"},
+	"SliceExclusionStrings": {
+		name: "SliceExclusionOrdered",
+		note: " (or SliceExclusion if the order of the result does not matter)",
+	},
+	"SliceExclusionInts": {
+		name: "SliceExclusionOrdered",
+		note: " (or SliceExclusion if the order of the result does not matter)",
+	},
+	"SliceIntersectStrings": {
+		name: "SliceIntersectionOrdered",
+		note: " (or SliceIntersection if the order of the result does not matter)",
+	},
+	"SliceIntersectInts": {
+		name: "SliceIntersectionOrdered",
+		note: " (or SliceIntersection if the order of the result does not matter)",
+	},
+	"SliceUniqInts":    {name: "FilterUniqueElements", note: uniqNote, mutatesInPlace: true},
+	"SliceUniqInt64s":  {name: "FilterUniqueElements", note: uniqNote, mutatesInPlace: true},
+	"SliceUniqStrings": {name: "FilterUniqueElements", note: uniqNote, mutatesInPlace: true},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{(*ast.CallExpr)(nil)}
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		callee := typeutil.StaticCallee(pass.TypesInfo, call)
+		if callee == nil || callee.Pkg() == nil || callee.Pkg().Path() != gobagPath {
+			return true
+		}
+		legacy := callee.Name()
+		r, ok := replacements[legacy]
+		if !ok {
+			return true
+		}
+
+		var ident *ast.Ident
+		switch fun := ast.Unparen(call.Fun).(type) {
+		case *ast.Ident:
+			ident = fun
+		case *ast.SelectorExpr:
+			ident = fun.Sel
+		default:
+			return true
+		}
+
+		diagnostic := analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: fmt.Sprintf("%s is deprecated: use %s%s", legacy, r.name, r.note),
+		}
+		if !r.mutatesInPlace || resultIsUsed(stack) {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("Replace %s with %s", legacy, r.name),
+				TextEdits: []analysis.TextEdit{{
+					Pos:     ident.Pos(),
+					End:     ident.End(),
+					NewText: []byte(r.name + typeArguments(pass, call, callee)),
+				}},
+			}}
+		}
+		pass.Report(diagnostic)
+		return true
+	})
+	return nil, nil
+}
+
+// typeArguments returns the explicit type arguments the replacement of the
+// legacy callee needs in call, which are none unless every argument is an
+// untyped nil and leaves nothing to infer them from.
+func typeArguments(pass *analysis.Pass, call *ast.CallExpr, callee *types.Func) string {
+	for _, arg := range call.Args {
+		if !pass.TypesInfo.Types[arg].IsNil() {
+			return ""
+		}
+	}
+	// Every legacy helper takes a slice of the element type first.
+	source := callee.Type().(*types.Signature).Params().At(0).Type().Underlying().(*types.Slice)
+	return "[" + types.TypeString(source.Elem(), types.RelativeTo(pass.Pkg)) + "]"
+}
+
+// resultIsUsed reports whether the call at the top of stack is used as a
+// value rather than as a bare expression, go or defer statement, all of which
+// discard its result.
+func resultIsUsed(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ParenExpr:
+			continue
+		case *ast.ExprStmt, *ast.GoStmt, *ast.DeferStmt:
+			return false
+		default:
+			return true
+		}
+	}
+	return true
+}
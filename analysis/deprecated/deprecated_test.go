@@ -0,0 +1,12 @@
+package deprecated_test
+
+import (
+	"testing"
+
+	"github.com/neumachen/gobag/analysis/deprecated"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), deprecated.Analyzer, "example")
+}
@@ -0,0 +1,32 @@
+package example
+
+import (
+	"github.com/neumachen/gobag"
+)
+
+func legacy(names []string, ids []int, bigIDs []int64) {
+	_ = gobag.ArrayContainsStr(names, "a") // want `ArrayContainsStr is deprecated: use SliceContains`
+
+	onlyNames, _ := gobag.SliceExclusionStrings(names, names) // want `SliceExclusionStrings is deprecated: use SliceExclusionOrdered \(or SliceExclusion if the order of the result does not matter\)`
+	_, onlyIDs := gobag.SliceExclusionInts(ids, ids)          // want `SliceExclusionInts is deprecated: use SliceExclusionOrdered`
+	_, _ = onlyNames, onlyIDs
+
+	_ = gobag.SliceIntersectStrings(names, names) // want `SliceIntersectStrings is deprecated: use SliceIntersectionOrdered`
+	_ = gobag.SliceIntersectInts(ids, ids)        // want `SliceIntersectInts is deprecated: use SliceIntersectionOrdered`
+
+	ids = gobag.SliceUniqInts(ids)           // want `SliceUniqInts is deprecated: use FilterUniqueElements; note that FilterUniqueElements returns a new slice`
+	bigIDs = (gobag.SliceUniqInt64s)(bigIDs) // want `SliceUniqInt64s is deprecated`
+	gobag.SliceUniqStrings(names)            // want `SliceUniqStrings is deprecated`
+	defer gobag.SliceUniqInts(ids)           // want `SliceUniqInts is deprecated`
+	go gobag.SliceUniqStrings(names)         // want `SliceUniqStrings is deprecated`
+	_, _ = ids, bigIDs
+
+	// Calls on untyped nil arguments leave nothing to infer the type
+	// parameter of the replacement from.
+	onlyNames, _ = gobag.SliceExclusionStrings(nil, nil) // want `SliceExclusionStrings is deprecated`
+	ids = gobag.SliceUniqInts(nil)                       // want `SliceUniqInts is deprecated`
+	_ = gobag.SliceIntersectInts(nil, ids)               // want `SliceIntersectInts is deprecated`
+
+	_ = gobag.SliceContains(names, "a")
+	_ = gobag.FilterUniqueElements(ids)
+}
@@ -0,0 +1,25 @@
+package gobag
+
+func ArrayContainsStr(source []string, target string) bool { return false }
+
+func SliceContains[T comparable](source []T, target T) bool { return false }
+
+func SliceExclusionStrings(source, reference []string) ([]string, []string) { return nil, nil }
+
+func SliceExclusionInts(source, reference []int) ([]int, []int) { return nil, nil }
+
+func SliceExclusionOrdered[T comparable](source, reference []T) ([]T, []T) { return nil, nil }
+
+func SliceIntersectStrings(sliceA, sliceB []string) []string { return nil }
+
+func SliceIntersectInts(sliceA, sliceB []int) []int { return nil }
+
+func SliceIntersectionOrdered[T comparable](source, target []T) []T { return nil }
+
+func SliceUniqInts(s []int) []int { return s }
+
+func SliceUniqInt64s(s []int64) []int64 { return s }
+
+func SliceUniqStrings(s []string) []string { return s }
+
+func FilterUniqueElements[T comparable](slice []T) []T { return slice }
@@ -1,6 +1,11 @@
 package gobag
 
-// SliceUniqInts ...
+// SliceUniqInts removes duplicate ints from s in place, keeping the first
+// occurrence of each value, and returns the deduplicated prefix of s. The
+// backing array of s is overwritten.
+//
+// Deprecated: Since Go 1.18, it is recommended to use FilterUniqueElements,
+// which returns a new slice and leaves its argument untouched.
 func SliceUniqInts(s []int) []int {
 	seen := make(map[int]struct{}, len(s))
 	j := 0
@@ -15,7 +20,12 @@ func SliceUniqInts(s []int) []int {
 	return s[:j]
 }
 
-// SliceUniqInts ...
+// SliceUniqInt64s removes duplicate int64s from s in place, keeping the first
+// occurrence of each value, and returns the deduplicated prefix of s. The
+// backing array of s is overwritten.
+//
+// Deprecated: Since Go 1.18, it is recommended to use FilterUniqueElements,
+// which returns a new slice and leaves its argument untouched.
 func SliceUniqInt64s(s []int64) []int64 {
 	seen := make(map[int64]struct{}, len(s))
 	j := 0
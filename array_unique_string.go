@@ -1,6 +1,11 @@
 package gobag
 
-// SliceUniqStrings ...
+// SliceUniqStrings removes duplicate strings from s in place, keeping the
+// first occurrence of each value, and returns the deduplicated prefix of s.
+// The backing array of s is overwritten.
+//
+// Deprecated: Since Go 1.18, it is recommended to use FilterUniqueElements,
+// which returns a new slice and leaves its argument untouched.
 func SliceUniqStrings(s []string) []string {
 	seen := make(map[string]struct{}, len(s))
 	j := 0
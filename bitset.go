@@ -0,0 +1,178 @@
+package gobag
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// Bitset is a set of non-negative ints stored as one bit per value up to the
+// largest one. It suits dense sets of small integers such as sequential IDs,
+// for which its set operations run word by word, 64 values at a time. For
+// sparse sets spanning a large range, use RoaringBitset.
+//
+// The zero value of Bitset is an empty set ready to use. A Bitset is not safe
+// for concurrent use without external synchronization.
+//
+// Bitset expresses the int helpers of this package without allocating maps,
+// sorting their results instead of keeping the order of their arguments:
+//
+//	NewBitset(a...).And(NewBitset(b...)).Ints()    // SliceIntersectInts(a, b)
+//	NewBitset(a...).AndNot(NewBitset(b...)).Ints() // first result of SliceExclusionInts(a, b)
+//	NewBitset(b...).AndNot(NewBitset(a...)).Ints() // second result of SliceExclusionInts(a, b)
+//	NewBitset(a...).Ints()                         // SliceUniqInts(a)
+type Bitset struct {
+	words []uint64
+}
+
+// NewBitset creates a new Bitset containing the given values. It panics if a
+// value is negative.
+func NewBitset(values ...int) *Bitset {
+	b := &Bitset{}
+	if len(values) > 0 {
+		b.words = make([]uint64, 0, max(slices.Max(values), 0)/64+1)
+	}
+	for _, v := range values {
+		b.Set(v)
+	}
+	return b
+}
+
+// Set adds i to the set. It panics if i is negative.
+func (b *Bitset) Set(i int) {
+	if i < 0 {
+		panic("gobag: negative bitset index")
+	}
+	w := i / 64
+	if w >= len(b.words) {
+		b.words = append(b.words, make([]uint64, w+1-len(b.words))...)
+	}
+	b.words[w] |= 1 << (i % 64)
+}
+
+// Clear removes i from the set. Values that are not present are ignored.
+func (b *Bitset) Clear(i int) {
+	if w := i / 64; i >= 0 && w < len(b.words) {
+		b.words[w] &^= 1 << (i % 64)
+	}
+}
+
+// Test reports whether i is present in the set.
+func (b *Bitset) Test(i int) bool {
+	w := i / 64
+	return i >= 0 && w < len(b.words) && b.words[w]&(1<<(i%64)) != 0
+}
+
+// Count returns the number of values in the set.
+func (b *Bitset) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Reset removes every value from the set, keeping its storage.
+func (b *Bitset) Reset() {
+	clear(b.words)
+}
+
+// Clone returns a copy of the set.
+func (b *Bitset) Clone() *Bitset {
+	return &Bitset{words: slices.Clone(b.words)}
+}
+
+// Equal reports whether b and other contain the same values.
+func (b *Bitset) Equal(other *Bitset) bool {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// And returns a new Bitset with the values present in both b and other.
+func (b *Bitset) And(other *Bitset) *Bitset {
+	words := make([]uint64, min(len(b.words), len(other.words)))
+	for i := range words {
+		words[i] = b.words[i] & other.words[i]
+	}
+	return &Bitset{words: words}
+}
+
+// Or returns a new Bitset with the values present in b, other, or both.
+func (b *Bitset) Or(other *Bitset) *Bitset {
+	return b.combine(other, func(x, y uint64) uint64 { return x | y })
+}
+
+// Xor returns a new Bitset with the values present in exactly one of b and
+// other.
+func (b *Bitset) Xor(other *Bitset) *Bitset {
+	return b.combine(other, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// AndNot returns a new Bitset with the values of b that are not present in
+// other.
+func (b *Bitset) AndNot(other *Bitset) *Bitset {
+	words := slices.Clone(b.words)
+	for i := range min(len(words), len(other.words)) {
+		words[i] &^= other.words[i]
+	}
+	return &Bitset{words: words}
+}
+
+// All returns an iterator over the values of the set in ascending order. The
+// set must not be modified during the iteration.
+func (b *Bitset) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				if !yield(i*64 + bits.TrailingZeros64(w)) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// Ints returns the values of the set in ascending order, or nil if the set is
+// empty.
+func (b *Bitset) Ints() []int {
+	count := b.Count()
+	if count == 0 {
+		return nil
+	}
+	values := make([]int, 0, count)
+	for v := range b.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// combine applies op to the words of b and other, the shorter one being
+// padded with zeros.
+func (b *Bitset) combine(other *Bitset, op func(x, y uint64) uint64) *Bitset {
+	words := make([]uint64, max(len(b.words), len(other.words)))
+	for i := range words {
+		var x, y uint64
+		if i < len(b.words) {
+			x = b.words[i]
+		}
+		if i < len(other.words) {
+			y = other.words[i]
+		}
+		words[i] = op(x, y)
+	}
+	return &Bitset{words: words}
+}
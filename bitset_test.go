@@ -0,0 +1,122 @@
+package gobag
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sortedSet returns the elements of s in ascending order, or nil if s is
+// empty.
+func sortedSet(s *Set[int]) []int {
+	values := s.Slice()
+	slices.Sort(values)
+	return values
+}
+
+func TestBitset(t *testing.T) {
+	t.Run("set, clear and test", func(t *testing.T) {
+		var b Bitset
+		require.False(t, b.Test(3))
+		b.Set(3)
+		b.Set(200)
+		b.Set(3)
+		require.True(t, b.Test(3))
+		require.True(t, b.Test(200))
+		require.False(t, b.Test(64))
+		require.False(t, b.Test(-1))
+		require.False(t, b.Test(1<<20))
+		require.Equal(t, 2, b.Count())
+
+		b.Clear(3)
+		b.Clear(1 << 20)
+		b.Clear(-5)
+		require.Equal(t, []int{200}, b.Ints())
+
+		b.Reset()
+		require.Zero(t, b.Count())
+		require.Nil(t, b.Ints())
+		require.Panics(t, func() { b.Set(-1) })
+		require.Panics(t, func() { NewBitset(1, -1) })
+	})
+
+	t.Run("algebra matches Set", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		for range 50 {
+			var a, b []int
+			for range r.IntN(300) {
+				a = append(a, r.IntN(500))
+			}
+			for range r.IntN(300) {
+				b = append(b, r.IntN(900))
+			}
+			sa, sb := NewSet(a...), NewSet(b...)
+			ba, bb := NewBitset(a...), NewBitset(b...)
+
+			require.Equal(t, sortedSet(sa), ba.Ints())
+			require.Equal(t, sa.Len(), ba.Count())
+			require.Equal(t, sortedSet(sa.Intersect(sb)), ba.And(bb).Ints())
+			require.Equal(t, sortedSet(sa.Union(sb)), ba.Or(bb).Ints())
+			require.Equal(t, sortedSet(sa.SymmetricDifference(sb)), ba.Xor(bb).Ints())
+			require.Equal(t, sortedSet(sa.Difference(sb)), ba.AndNot(bb).Ints())
+			require.Equal(t, sortedSet(sb.Difference(sa)), bb.AndNot(ba).Ints())
+		}
+	})
+
+	t.Run("expresses the int helpers", func(t *testing.T) {
+		a := []int{5, 1, 9, 1, 3}
+		b := []int{3, 4, 5}
+
+		expected := SliceIntersectInts(a, b)
+		slices.Sort(expected)
+		require.Equal(t, expected, NewBitset(a...).And(NewBitset(b...)).Ints())
+
+		onlyA, onlyB := SliceExclusionInts(a, b)
+		slices.Sort(onlyA)
+		slices.Sort(onlyB)
+		require.Equal(t, onlyA, NewBitset(a...).AndNot(NewBitset(b...)).Ints())
+		require.Equal(t, onlyB, NewBitset(b...).AndNot(NewBitset(a...)).Ints())
+
+		expected = SliceUniqInts(slices.Clone(a))
+		slices.Sort(expected)
+		require.Equal(t, expected, NewBitset(a...).Ints())
+	})
+
+	t.Run("equal and clone", func(t *testing.T) {
+		a := NewBitset(1, 1000)
+		a.Clear(1000)
+		require.True(t, a.Equal(NewBitset(1)))
+		require.True(t, NewBitset(1).Equal(a))
+		require.False(t, a.Equal(NewBitset(2)))
+
+		c := a.Clone()
+		c.Set(7)
+		require.False(t, a.Test(7))
+	})
+
+	t.Run("early stop", func(t *testing.T) {
+		var got []int
+		for v := range NewBitset(1, 70, 140).All() {
+			got = append(got, v)
+			if len(got) == 2 {
+				break
+			}
+		}
+		require.Equal(t, []int{1, 70}, got)
+	})
+}
+
+func BenchmarkBitsetAnd(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 1))
+	ids := make([]int, 10000)
+	for i := range ids {
+		ids[i] = r.IntN(50000)
+	}
+	x, y := NewBitset(ids[:5000]...), NewBitset(ids[5000:]...)
+	b.ResetTimer()
+	for range b.N {
+		x.And(y)
+	}
+}
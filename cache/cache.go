@@ -0,0 +1,423 @@
+// Package cache provides a generic in-memory cache with LRU or LFU eviction,
+// per-entry expiration, cost-based capacity and deduplicated loading of
+// missing entries.
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/neumachen/gobag/container"
+)
+
+// ErrNoLoader is returned by GetOrLoad when the cache has no Loader.
+var ErrNoLoader = errors.New("cache: no loader configured")
+
+var errLoaderPanicked = errors.New("cache: loader panicked")
+
+// Policy selects which entry a full cache evicts.
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry, breaking ties by evicting
+	// the least recently used one.
+	LFU
+)
+
+// EvictionReason tells why an entry left the cache.
+type EvictionReason int
+
+const (
+	// EvictedCapacity means the entry was evicted to honor the capacity.
+	EvictedCapacity EvictionReason = iota
+	// EvictedExpired means the entry outlived its time to live.
+	EvictedExpired
+	// EvictedDeleted means the entry was removed by Delete or Clear.
+	EvictedDeleted
+	// EvictedReplaced means the entry was overwritten by Set.
+	EvictedReplaced
+)
+
+// String returns the name of the reason.
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	case EvictedDeleted:
+		return "deleted"
+	case EvictedReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// Clock tells the current time. It allows tests to control expiration.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns the time reported by the function.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock reading the system time.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// Options configures a Cache. The zero value describes an unbounded LRU
+// cache whose entries never expire.
+type Options[K comparable, V any] struct {
+	// Policy selects the eviction policy.
+	Policy Policy
+	// Capacity is the maximum total cost of the entries, or unbounded if
+	// zero.
+	Capacity int64
+	// Cost computes the cost of an entry. When nil every entry costs 1, so
+	// Capacity bounds the number of entries.
+	Cost func(key K, value V) int64
+	// TTL is the time to live of entries stored with Set, or forever if
+	// zero.
+	TTL time.Duration
+	// Clock tells the time used for expiration. It defaults to SystemClock.
+	Clock Clock
+	// OnEvict is called, without any lock held, whenever an entry leaves
+	// the cache.
+	OnEvict func(key K, value V, reason EvictionReason)
+	// Loader computes the value of missing entries for GetOrLoad.
+	Loader func(ctx context.Context, key K) (V, error)
+}
+
+// Stats holds the counters of a Cache.
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+	Loads       uint64
+	LoadErrors  uint64
+}
+
+// HitRate returns the ratio of hits to lookups, or zero if there were none.
+func (s Stats) HitRate() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	cost      int64
+	expiresAt time.Time
+	// frequency and lastAccess order the entries for eviction.
+	frequency  uint64
+	lastAccess uint64
+	handle     *container.Handle[*entry[K, V]]
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Cache is an in-memory key-value cache safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu        sync.Mutex
+	options   Options[K, V]
+	entries   map[K]*entry[K, V]
+	order     *container.PriorityQueue[*entry[K, V]]
+	totalCost int64
+	tick      uint64
+	stats     Stats
+	loads     map[K]*loadCall[V]
+}
+
+// New creates an empty Cache configured by options.
+//
+// Example:
+//
+//	geocodes := cache.New(cache.Options[string, gobag.GeoPoint]{
+//		Capacity: 10000,
+//		TTL:      time.Hour,
+//		Loader:   geocode,
+//	})
+func New[K comparable, V any](options Options[K, V]) *Cache[K, V] {
+	if options.Clock == nil {
+		options.Clock = SystemClock
+	}
+	less := func(a, b *entry[K, V]) bool { return a.lastAccess < b.lastAccess }
+	if options.Policy == LFU {
+		less = func(a, b *entry[K, V]) bool {
+			if a.frequency != b.frequency {
+				return a.frequency < b.frequency
+			}
+			return a.lastAccess < b.lastAccess
+		}
+	}
+	return &Cache[K, V]{
+		options: options,
+		entries: make(map[K]*entry[K, V]),
+		order:   container.NewPriorityQueue(less),
+		loads:   make(map[K]*loadCall[V]),
+	}
+}
+
+// Get returns the value stored for key. The boolean result is false if there
+// is no such entry or if it has expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	value, ok, evictions := c.get(key)
+	c.mu.Unlock()
+	c.notify(evictions)
+	return value, ok
+}
+
+// Set stores value for key with the default time to live of the cache.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.options.TTL)
+}
+
+// SetWithTTL stores value for key, expiring after ttl, or never if ttl is
+// zero. An entry whose cost exceeds the capacity of the cache is not stored
+// and is reported to OnEvict right away.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	evictions := c.set(key, value, ttl)
+	c.mu.Unlock()
+	c.notify(evictions)
+}
+
+// Delete removes the entry stored for key and reports whether there was one.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	var evictions []eviction[K, V]
+	if ok {
+		evictions = append(evictions, c.remove(e, EvictedDeleted))
+	}
+	c.mu.Unlock()
+	c.notify(evictions)
+	return ok
+}
+
+// Clear removes every entry of the cache.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	evictions := make([]eviction[K, V], 0, len(c.entries))
+	for _, e := range c.entries {
+		evictions = append(evictions, c.remove(e, EvictedDeleted))
+	}
+	c.mu.Unlock()
+	c.notify(evictions)
+}
+
+// DeleteExpired removes every expired entry and returns how many there were.
+// Expired entries are otherwise removed lazily when looked up or evicted.
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	now := c.options.Clock.Now()
+	var evictions []eviction[K, V]
+	for _, e := range c.entries {
+		if e.expired(now) {
+			evictions = append(evictions, c.remove(e, EvictedExpired))
+		}
+	}
+	c.mu.Unlock()
+	c.notify(evictions)
+	return len(evictions)
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// not removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Cost returns the total cost of the entries in the cache.
+func (c *Cache[K, V]) Cost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.totalCost
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// GetOrLoad returns the value stored for key, calling the Loader of the cache
+// to compute and store it on a miss. Concurrent misses for the same key share
+// a single Loader call, which runs with the context of the caller that
+// started it; a caller whose ctx is done stops waiting for a load started by
+// another caller. Errors returned by the Loader are not cached.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K) (V, error) {
+	if c.options.Loader == nil {
+		var zero V
+		return zero, ErrNoLoader
+	}
+
+	c.mu.Lock()
+	value, ok, evictions := c.get(key)
+	if ok {
+		c.mu.Unlock()
+		c.notify(evictions)
+		return value, nil
+	}
+	call, loading := c.loads[key]
+	if !loading {
+		call = &loadCall[V]{done: make(chan struct{})}
+		c.loads[key] = call
+	}
+	c.mu.Unlock()
+	c.notify(evictions)
+
+	if loading {
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+
+	c.load(ctx, key, call)
+	return call.value, call.err
+}
+
+// load runs the Loader for key on behalf of call and publishes its result.
+// If the Loader panics, callers waiting for call receive errLoaderPanicked and
+// the panic propagates to the caller that started the load.
+func (c *Cache[K, V]) load(ctx context.Context, key K, call *loadCall[V]) {
+	var evictions []eviction[K, V]
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, key)
+		c.stats.Loads++
+		if call.err != nil {
+			c.stats.LoadErrors++
+		} else {
+			evictions = c.set(key, call.value, c.options.TTL)
+		}
+		c.mu.Unlock()
+		close(call.done)
+		c.notify(evictions)
+	}()
+
+	call.err = errLoaderPanicked
+	call.value, call.err = c.options.Loader(ctx, key)
+}
+
+func (c *Cache[K, V]) get(key K) (V, bool, []eviction[K, V]) {
+	var zero V
+	e, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return zero, false, nil
+	}
+	if e.expired(c.options.Clock.Now()) {
+		c.stats.Misses++
+		return zero, false, []eviction[K, V]{c.remove(e, EvictedExpired)}
+	}
+
+	c.stats.Hits++
+	c.touch(e)
+	return e.value, true, nil
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) []eviction[K, V] {
+	var evictions []eviction[K, V]
+	if old, ok := c.entries[key]; ok {
+		evictions = append(evictions, c.remove(old, EvictedReplaced))
+	}
+
+	cost := int64(1)
+	if c.options.Cost != nil {
+		cost = c.options.Cost(key, value)
+	}
+	if c.options.Capacity > 0 && cost > c.options.Capacity {
+		c.stats.Evictions++
+		return append(evictions, eviction[K, V]{key: key, value: value, reason: EvictedCapacity})
+	}
+
+	now := c.options.Clock.Now()
+	e := &entry[K, V]{key: key, value: value, cost: cost}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+	c.tick++
+	e.lastAccess = c.tick
+	e.frequency = 1
+
+	// Make room before inserting the new entry, which would otherwise be
+	// the first victim of the LFU policy.
+	for c.options.Capacity > 0 && c.totalCost+cost > c.options.Capacity {
+		victim, _ := c.order.Peek()
+		reason := EvictedCapacity
+		if victim.expired(now) {
+			reason = EvictedExpired
+		}
+		evictions = append(evictions, c.remove(victim, reason))
+	}
+
+	e.handle = c.order.Push(e)
+	c.entries[key] = e
+	c.totalCost += cost
+	return evictions
+}
+
+func (c *Cache[K, V]) touch(e *entry[K, V]) {
+	c.tick++
+	e.lastAccess = c.tick
+	e.frequency++
+	c.order.Update(e.handle, e)
+}
+
+func (c *Cache[K, V]) remove(e *entry[K, V], reason EvictionReason) eviction[K, V] {
+	c.order.Remove(e.handle)
+	delete(c.entries, e.key)
+	c.totalCost -= e.cost
+	switch reason {
+	case EvictedCapacity:
+		c.stats.Evictions++
+	case EvictedExpired:
+		c.stats.Expirations++
+	}
+	return eviction[K, V]{key: e.key, value: e.value, reason: reason}
+}
+
+func (c *Cache[K, V]) notify(evictions []eviction[K, V]) {
+	if c.options.OnEvict == nil {
+		return
+	}
+	for _, e := range evictions {
+		c.options.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
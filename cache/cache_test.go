@@ -0,0 +1,264 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type recordedEviction struct {
+	key    string
+	value  int
+	reason EvictionReason
+}
+
+func recordEvictions(evictions *[]recordedEviction) func(string, int, EvictionReason) {
+	return func(key string, value int, reason EvictionReason) {
+		*evictions = append(*evictions, recordedEviction{key: key, value: value, reason: reason})
+	}
+}
+
+func TestCache_LRU(t *testing.T) {
+	var evictions []recordedEviction
+	c := New(Options[string, int]{Capacity: 2, OnEvict: recordEvictions(&evictions)})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	_, ok := c.Get("a") // b is now the least recently used
+	require.True(t, ok)
+	c.Set("c", 3)
+
+	_, ok = c.Get("b")
+	require.False(t, ok)
+	v, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, []recordedEviction{{key: "b", value: 2, reason: EvictedCapacity}}, evictions)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(2), stats.Hits)
+	require.Equal(t, uint64(1), stats.Misses)
+	require.Equal(t, uint64(1), stats.Evictions)
+	require.InDelta(t, 2.0/3, stats.HitRate(), 1e-9)
+}
+
+func TestCache_LFU(t *testing.T) {
+	c := New(Options[string, int]{Policy: LFU, Capacity: 2})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	for range 3 {
+		c.Get("a")
+	}
+	c.Get("b")
+	c.Set("c", 3) // b was used less often than a
+
+	_, ok := c.Get("b")
+	require.False(t, ok)
+	_, ok = c.Get("a")
+	require.True(t, ok)
+
+	c.Get("c")
+	c.Set("d", 4) // a has been used 5 times, c only twice
+	_, ok = c.Get("c")
+	require.False(t, ok)
+	_, ok = c.Get("a")
+	require.True(t, ok)
+}
+
+func TestCache_TTL(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var evictions []recordedEviction
+	c := New(Options[string, int]{TTL: time.Minute, Clock: clock, OnEvict: recordEvictions(&evictions)})
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	c.SetWithTTL("forever", 3, 0)
+
+	clock.Advance(time.Minute)
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	_, ok = c.Get("b")
+	require.True(t, ok)
+
+	clock.Advance(time.Hour)
+	require.Equal(t, 1, c.DeleteExpired())
+	_, ok = c.Get("forever")
+	require.True(t, ok)
+
+	require.Equal(t, []recordedEviction{
+		{key: "a", value: 1, reason: EvictedExpired},
+		{key: "b", value: 2, reason: EvictedExpired},
+	}, evictions)
+	require.Equal(t, uint64(2), c.Stats().Expirations)
+}
+
+func TestCache_Cost(t *testing.T) {
+	var evictions []recordedEviction
+	c := New(Options[string, int]{
+		Capacity: 10,
+		Cost:     func(_ string, value int) int64 { return int64(value) },
+		OnEvict:  recordEvictions(&evictions),
+	})
+
+	c.Set("a", 4)
+	c.Set("b", 4)
+	c.Set("c", 4) // evicts a
+	require.Equal(t, int64(8), c.Cost())
+
+	c.Set("huge", 11) // never stored
+	_, ok := c.Get("huge")
+	require.False(t, ok)
+	require.Equal(t, 2, c.Len())
+
+	c.Set("b", 1) // replaced, frees room
+	require.Equal(t, int64(5), c.Cost())
+
+	require.True(t, c.Delete("c"))
+	require.False(t, c.Delete("c"))
+	c.Clear()
+	require.Equal(t, 0, c.Len())
+	require.Equal(t, int64(0), c.Cost())
+
+	require.Equal(t, []recordedEviction{
+		{key: "a", value: 4, reason: EvictedCapacity},
+		{key: "huge", value: 11, reason: EvictedCapacity},
+		{key: "b", value: 4, reason: EvictedReplaced},
+		{key: "c", value: 4, reason: EvictedDeleted},
+		{key: "b", value: 1, reason: EvictedDeleted},
+	}, evictions)
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	t.Run("deduplicates concurrent misses", func(t *testing.T) {
+		var calls atomic.Int64
+		release := make(chan struct{})
+		c := New(Options[string, int]{
+			Loader: func(_ context.Context, key string) (int, error) {
+				calls.Add(1)
+				<-release
+				return len(key), nil
+			},
+		})
+
+		var wg sync.WaitGroup
+		results := make([]int, 10)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := c.GetOrLoad(context.Background(), "hello")
+				require.NoError(t, err)
+				results[i] = v
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int64(1), calls.Load())
+		for _, v := range results {
+			require.Equal(t, 5, v)
+		}
+		v, ok := c.Get("hello")
+		require.True(t, ok)
+		require.Equal(t, 5, v)
+		require.Equal(t, uint64(1), c.Stats().Loads)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		fail := true
+		c := New(Options[string, int]{
+			Loader: func(_ context.Context, _ string) (int, error) {
+				if fail {
+					return 0, errBoom
+				}
+				return 42, nil
+			},
+		})
+
+		_, err := c.GetOrLoad(context.Background(), "k")
+		require.ErrorIs(t, err, errBoom)
+		fail = false
+		v, err := c.GetOrLoad(context.Background(), "k")
+		require.NoError(t, err)
+		require.Equal(t, 42, v)
+		require.Equal(t, uint64(1), c.Stats().LoadErrors)
+	})
+
+	t.Run("waiters honor their context", func(t *testing.T) {
+		release := make(chan struct{})
+		started := make(chan struct{})
+		c := New(Options[string, int]{
+			Loader: func(_ context.Context, _ string) (int, error) {
+				close(started)
+				<-release
+				return 1, nil
+			},
+		})
+
+		go c.GetOrLoad(context.Background(), "k")
+		<-started
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.GetOrLoad(ctx, "k")
+		require.ErrorIs(t, err, context.Canceled)
+		close(release)
+	})
+
+	t.Run("loader panics release waiters", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		c := New(Options[string, int]{
+			Loader: func(_ context.Context, _ string) (int, error) {
+				close(started)
+				<-release
+				panic("kaboom")
+			},
+		})
+
+		go func() {
+			defer func() { _ = recover() }()
+			c.GetOrLoad(context.Background(), "k")
+		}()
+		<-started
+		waiter := make(chan error)
+		go func() {
+			_, err := c.GetOrLoad(context.Background(), "k")
+			waiter <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		require.ErrorIs(t, <-waiter, errLoaderPanicked)
+	})
+
+	t.Run("without loader", func(t *testing.T) {
+		c := New(Options[string, int]{})
+		_, err := c.GetOrLoad(context.Background(), "k")
+		require.ErrorIs(t, err, ErrNoLoader)
+	})
+}
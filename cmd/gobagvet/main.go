@@ -0,0 +1,21 @@
+// Command gobagvet reports calls to the legacy gobag helpers and suggests
+// their generic replacements.
+//
+// It can be run standalone, optionally applying the suggested fixes:
+//
+//	go install github.com/neumachen/gobag/cmd/gobagvet@latest
+//	gobagvet -fix ./...
+//
+// or through go vet:
+//
+//	go vet -vettool=$(which gobagvet) ./...
+package main
+
+import (
+	"github.com/neumachen/gobag/analysis/deprecated"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(deprecated.Analyzer)
+}
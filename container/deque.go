@@ -0,0 +1,154 @@
+// Package container provides generic container types: a double-ended queue,
+// a fixed-capacity ring buffer, a priority queue with updatable entries, an
+// ordered map and a prefix trie.
+//
+// The containers are not safe for concurrent use. The queues have a Sync
+// counterpart, such as SyncDeque, guarding every operation with a mutex,
+// while OrderedMap offers snapshots that can be read concurrently.
+package container
+
+import "iter"
+
+const minDequeCapacity = 8
+
+// Deque is a double-ended queue backed by a growable ring buffer. Pushing and
+// popping at either end run in amortized O(1) time.
+//
+// The zero value of Deque is an empty deque ready to use.
+type Deque[T any] struct {
+	buffer []T
+	head   int
+	length int
+}
+
+// NewDeque creates a new Deque holding the given elements, front to back.
+func NewDeque[T any](elements ...T) *Deque[T] {
+	d := &Deque[T]{buffer: make([]T, max(len(elements), minDequeCapacity))}
+	copy(d.buffer, elements)
+	d.length = len(elements)
+	return d
+}
+
+// Len returns the number of elements in the deque.
+func (d *Deque[T]) Len() int {
+	return d.length
+}
+
+// PushBack adds element at the back of the deque.
+func (d *Deque[T]) PushBack(element T) {
+	d.grow()
+	d.buffer[d.index(d.length)] = element
+	d.length++
+}
+
+// PushFront adds element at the front of the deque.
+func (d *Deque[T]) PushFront(element T) {
+	d.grow()
+	d.head = d.index(len(d.buffer) - 1)
+	d.buffer[d.head] = element
+	d.length++
+}
+
+// PopFront removes and returns the element at the front of the deque. The
+// boolean result is false if the deque is empty.
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.length == 0 {
+		return zero, false
+	}
+	element := d.buffer[d.head]
+	d.buffer[d.head] = zero
+	d.head = d.index(1)
+	d.length--
+	return element, true
+}
+
+// PopBack removes and returns the element at the back of the deque. The
+// boolean result is false if the deque is empty.
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.length == 0 {
+		return zero, false
+	}
+	i := d.index(d.length - 1)
+	element := d.buffer[i]
+	d.buffer[i] = zero
+	d.length--
+	return element, true
+}
+
+// Front returns the element at the front of the deque without removing it.
+// The boolean result is false if the deque is empty.
+func (d *Deque[T]) Front() (T, bool) {
+	if d.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buffer[d.head], true
+}
+
+// Back returns the element at the back of the deque without removing it. The
+// boolean result is false if the deque is empty.
+func (d *Deque[T]) Back() (T, bool) {
+	if d.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buffer[d.index(d.length-1)], true
+}
+
+// At returns the i-th element from the front of the deque. It panics if i is
+// out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.length {
+		panic("container: deque index out of range")
+	}
+	return d.buffer[d.index(i)]
+}
+
+// Clear removes all elements from the deque, keeping its allocated capacity.
+func (d *Deque[T]) Clear() {
+	clear(d.buffer)
+	d.head, d.length = 0, 0
+}
+
+// All returns an iterator over the elements of the deque, front to back. The
+// deque must not be modified during the iteration.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range d.length {
+			if !yield(d.buffer[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the deque, back to
+// front. The deque must not be modified during the iteration.
+func (d *Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := d.length - 1; i >= 0; i-- {
+			if !yield(d.buffer[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// index maps the i-th position from the front to an index of the buffer.
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.buffer)
+}
+
+// grow doubles the buffer when it is full, unwrapping the elements to its
+// start.
+func (d *Deque[T]) grow() {
+	if d.length < len(d.buffer) {
+		return
+	}
+	buffer := make([]T, max(2*len(d.buffer), minDequeCapacity))
+	n := copy(buffer, d.buffer[d.head:])
+	copy(buffer[n:], d.buffer[:d.head])
+	d.buffer, d.head = buffer, 0
+}
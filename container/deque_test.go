@@ -0,0 +1,75 @@
+package container
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeque(t *testing.T) {
+	t.Run("zero value", func(t *testing.T) {
+		var d Deque[int]
+		_, ok := d.PopFront()
+		require.False(t, ok)
+		_, ok = d.Back()
+		require.False(t, ok)
+
+		d.PushFront(1)
+		require.Equal(t, 1, d.Len())
+		require.Equal(t, 1, d.At(0))
+	})
+
+	t.Run("both ends with growth", func(t *testing.T) {
+		d := NewDeque(3, 4)
+		for i := 2; i >= 0; i-- {
+			d.PushFront(i)
+		}
+		for i := 5; i < 40; i++ {
+			d.PushBack(i)
+		}
+		require.Equal(t, 40, d.Len())
+
+		expected := make([]int, 40)
+		for i := range expected {
+			expected[i] = i
+			require.Equal(t, i, d.At(i))
+		}
+		require.Equal(t, expected, slices.Collect(d.All()))
+		slices.Reverse(expected)
+		require.Equal(t, expected, slices.Collect(d.Backward()))
+
+		front, _ := d.Front()
+		back, _ := d.Back()
+		require.Equal(t, 0, front)
+		require.Equal(t, 39, back)
+
+		for i := range 20 {
+			v, ok := d.PopFront()
+			require.True(t, ok)
+			require.Equal(t, i, v)
+			v, ok = d.PopBack()
+			require.True(t, ok)
+			require.Equal(t, 39-i, v)
+		}
+		require.Equal(t, 0, d.Len())
+		require.Panics(t, func() { d.At(0) })
+	})
+
+	t.Run("wrap around", func(t *testing.T) {
+		d := NewDeque[int]()
+		for i := range 100 {
+			d.PushBack(i)
+			if i%3 != 0 {
+				d.PopFront()
+			}
+		}
+		require.Equal(t, 34, d.Len())
+		require.Equal(t, 66, d.At(0))
+		require.Equal(t, 99, d.At(33))
+
+		d.Clear()
+		require.Equal(t, 0, d.Len())
+		require.Nil(t, slices.Collect(d.All()))
+	})
+}
@@ -0,0 +1,550 @@
+package container
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// orderedMapDegree is the minimum degree of the B-tree backing OrderedMap:
+// every node but the root holds between degree-1 and 2*degree-1 entries.
+const orderedMapDegree = 32
+
+const (
+	minNodeEntries = orderedMapDegree - 1
+	maxNodeEntries = 2*orderedMapDegree - 1
+)
+
+// cowToken identifies the OrderedMap allowed to modify a node in place. Nodes
+// whose token differs from the token of a map are shared with a snapshot and
+// are cloned before being modified.
+type cowToken struct {
+	// padding gives the struct a non-zero size, so that every token has a
+	// distinct address.
+	_ byte
+}
+
+type mapEntry[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+}
+
+type mapNode[K cmp.Ordered, V any] struct {
+	entries  []mapEntry[K, V]
+	children []*mapNode[K, V]
+	// size is the number of entries in the subtree rooted at the node.
+	size int
+	cow  *cowToken
+}
+
+// OrderedMap is a map whose entries are kept sorted by key in a B-tree.
+// Lookups, insertions, deletions, rank and select queries run in O(log n)
+// time, and iterating over a range of k entries costs O(log n + k).
+//
+// Snapshot returns a copy of the map in O(1) time: both maps then share their
+// nodes and clone them lazily when either is modified. A snapshot can be read
+// by any number of goroutines while the original map keeps being modified by
+// its single writer.
+//
+// The zero value of OrderedMap is an empty map ready to use. An OrderedMap is
+// not safe for concurrent use when one of the goroutines modifies it.
+type OrderedMap[K cmp.Ordered, V any] struct {
+	root *mapNode[K, V]
+	cow  *cowToken
+}
+
+// NewOrderedMap creates an empty OrderedMap.
+func NewOrderedMap[K cmp.Ordered, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{cow: new(cowToken)}
+}
+
+// Len returns the number of entries in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	if m.root == nil {
+		return 0
+	}
+	return m.root.size
+}
+
+// Get returns the value stored for key. The boolean result is false if there
+// is no such entry.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	for n := m.root; n != nil; {
+		i, found := n.find(key)
+		if found {
+			return n.entries[i].value, true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	var zero V
+	return zero, false
+}
+
+// Set stores value for key and reports whether it replaced an existing entry.
+func (m *OrderedMap[K, V]) Set(key K, value V) bool {
+	if m.cow == nil {
+		m.cow = new(cowToken)
+	}
+	if m.root == nil {
+		m.root = &mapNode[K, V]{cow: m.cow}
+	}
+	m.root = m.mutable(m.root)
+	if len(m.root.entries) == maxNodeEntries {
+		left := m.root
+		middle, right := m.split(left, maxNodeEntries/2)
+		m.root = &mapNode[K, V]{
+			entries:  []mapEntry[K, V]{middle},
+			children: []*mapNode[K, V]{left, right},
+			size:     left.size + 1 + right.size,
+			cow:      m.cow,
+		}
+	}
+	return m.insert(m.root, mapEntry[K, V]{key: key, value: value})
+}
+
+// Delete removes the entry stored for key and reports whether there was one.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	if m.root == nil {
+		return false
+	}
+	m.root = m.mutable(m.root)
+	_, removed := m.remove(m.root, key, removeKey)
+	if len(m.root.entries) == 0 {
+		if m.root.leaf() {
+			m.root = nil
+		} else {
+			m.root = m.root.children[0]
+		}
+	}
+	return removed
+}
+
+// Clear removes every entry of the map. Snapshots are left untouched.
+func (m *OrderedMap[K, V]) Clear() {
+	m.root = nil
+}
+
+// Snapshot returns a copy of the map in O(1) time. The copy and the original
+// map are independent: modifying either does not affect the other.
+func (m *OrderedMap[K, V]) Snapshot() *OrderedMap[K, V] {
+	// Both maps get a fresh token so that neither modifies the nodes they
+	// now share.
+	m.cow = new(cowToken)
+	return &OrderedMap[K, V]{root: m.root, cow: new(cowToken)}
+}
+
+// Min returns the entry with the smallest key. The boolean result is false
+// if the map is empty.
+func (m *OrderedMap[K, V]) Min() (K, V, bool) {
+	if m.root == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := m.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return n.entries[0].key, n.entries[0].value, true
+}
+
+// Max returns the entry with the largest key. The boolean result is false if
+// the map is empty.
+func (m *OrderedMap[K, V]) Max() (K, V, bool) {
+	if m.root == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := m.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	last := n.entries[len(n.entries)-1]
+	return last.key, last.value, true
+}
+
+// Floor returns the entry with the largest key less than or equal to key.
+// The boolean result is false if there is none.
+func (m *OrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	var best *mapEntry[K, V]
+	for n := m.root; n != nil; {
+		i, found := n.find(key)
+		if found {
+			return n.entries[i].key, n.entries[i].value, true
+		}
+		if i > 0 {
+			best = &n.entries[i-1]
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return unpackEntry(best)
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to
+// key. The boolean result is false if there is none.
+func (m *OrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *mapEntry[K, V]
+	for n := m.root; n != nil; {
+		i, found := n.find(key)
+		if found {
+			return n.entries[i].key, n.entries[i].value, true
+		}
+		if i < len(n.entries) {
+			best = &n.entries[i]
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return unpackEntry(best)
+}
+
+// Rank returns the number of keys of the map strictly less than key, which is
+// the index key has or would have in the sorted sequence of keys.
+func (m *OrderedMap[K, V]) Rank(key K) int {
+	rank := 0
+	for n := m.root; n != nil; {
+		i, found := n.find(key)
+		if !n.leaf() {
+			for _, child := range n.children[:i] {
+				rank += child.size
+			}
+		}
+		rank += i
+		if found || n.leaf() {
+			if found && !n.leaf() {
+				rank += n.children[i].size
+			}
+			break
+		}
+		n = n.children[i]
+	}
+	return rank
+}
+
+// Select returns the entry at index i of the map in key order, starting at
+// zero. The boolean result is false if i is out of range.
+func (m *OrderedMap[K, V]) Select(i int) (K, V, bool) {
+	if i < 0 || i >= m.Len() {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := m.root
+	for !n.leaf() {
+		for j, child := range n.children {
+			if i < child.size {
+				n = child
+				break
+			}
+			i -= child.size
+			if i == 0 {
+				return n.entries[j].key, n.entries[j].value, true
+			}
+			i--
+		}
+	}
+	return n.entries[i].key, n.entries[i].value, true
+}
+
+// All returns an iterator over the entries of the map in ascending key order.
+// The map must not be modified during the iteration; iterate over a Snapshot
+// instead if it is.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, nil, nil, yield)
+	}
+}
+
+// Backward returns an iterator over the entries of the map in descending key
+// order. The map must not be modified during the iteration.
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		descend(m.root, yield)
+	}
+}
+
+// Range returns an iterator over the entries whose key is in [lo, hi), in
+// ascending key order. The map must not be modified during the iteration.
+//
+// Example:
+//
+//	for day, total := range totals.Range("2024-01-01", "2024-02-01") {
+//		fmt.Println(day, total)
+//	}
+func (m *OrderedMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, &lo, &hi, yield)
+	}
+}
+
+// Keys returns an iterator over the keys of the map in ascending order.
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func unpackEntry[K cmp.Ordered, V any](e *mapEntry[K, V]) (K, V, bool) {
+	if e == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return e.key, e.value, true
+}
+
+// ascend yields the entries of the subtree rooted at n whose key is in
+// [lo, hi), a nil bound being unbounded. It returns false once yield does.
+func ascend[K cmp.Ordered, V any](n *mapNode[K, V], lo, hi *K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	start := 0
+	if lo != nil {
+		start, _ = n.find(*lo)
+	}
+	for i := start; i <= len(n.entries); i++ {
+		if !n.leaf() && !ascend(n.children[i], lo, hi, yield) {
+			return false
+		}
+		if i == len(n.entries) {
+			break
+		}
+		e := n.entries[i]
+		if hi != nil && e.key >= *hi {
+			return false
+		}
+		if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func descend[K cmp.Ordered, V any](n *mapNode[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	for i := len(n.entries); i >= 0; i-- {
+		if !n.leaf() && !descend(n.children[i], yield) {
+			return false
+		}
+		if i == 0 {
+			break
+		}
+		if !yield(n.entries[i-1].key, n.entries[i-1].value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *mapNode[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// find returns the index of key in the entries of n, or the index of the
+// child that may hold it.
+func (n *mapNode[K, V]) find(key K) (int, bool) {
+	return slices.BinarySearchFunc(n.entries, key, func(e mapEntry[K, V], key K) int {
+		return cmp.Compare(e.key, key)
+	})
+}
+
+// mutable returns n if the map owns it, or a clone owned by the map.
+func (m *OrderedMap[K, V]) mutable(n *mapNode[K, V]) *mapNode[K, V] {
+	if n.cow == m.cow {
+		return n
+	}
+	clone := &mapNode[K, V]{
+		entries: make([]mapEntry[K, V], len(n.entries), maxNodeEntries),
+		size:    n.size,
+		cow:     m.cow,
+	}
+	copy(clone.entries, n.entries)
+	if !n.leaf() {
+		clone.children = make([]*mapNode[K, V], len(n.children), maxNodeEntries+1)
+		copy(clone.children, n.children)
+	}
+	return clone
+}
+
+// mutableChild makes the i-th child of the mutable node n mutable.
+func (m *OrderedMap[K, V]) mutableChild(n *mapNode[K, V], i int) *mapNode[K, V] {
+	n.children[i] = m.mutable(n.children[i])
+	return n.children[i]
+}
+
+// split splits the mutable node n around its i-th entry, which it returns
+// along with the new right sibling of n.
+func (m *OrderedMap[K, V]) split(n *mapNode[K, V], i int) (mapEntry[K, V], *mapNode[K, V]) {
+	middle := n.entries[i]
+	right := &mapNode[K, V]{cow: m.cow}
+	right.entries = append(make([]mapEntry[K, V], 0, maxNodeEntries), n.entries[i+1:]...)
+	clear(n.entries[i:])
+	n.entries = n.entries[:i]
+	right.size = len(right.entries)
+	if !n.leaf() {
+		right.children = append(make([]*mapNode[K, V], 0, maxNodeEntries+1), n.children[i+1:]...)
+		clear(n.children[i+1:])
+		n.children = n.children[:i+1]
+		for _, child := range right.children {
+			right.size += child.size
+		}
+	}
+	n.size -= right.size + 1
+	return middle, right
+}
+
+// insert inserts e into the subtree rooted at the mutable, non-full node n,
+// and reports whether it replaced an existing entry.
+func (m *OrderedMap[K, V]) insert(n *mapNode[K, V], e mapEntry[K, V]) bool {
+	i, found := n.find(e.key)
+	if found {
+		n.entries[i] = e
+		return true
+	}
+	if n.leaf() {
+		n.entries = slices.Insert(n.entries, i, e)
+		n.size++
+		return false
+	}
+
+	child := m.mutableChild(n, i)
+	if len(child.entries) == maxNodeEntries {
+		middle, right := m.split(child, maxNodeEntries/2)
+		n.entries = slices.Insert(n.entries, i, middle)
+		n.children = slices.Insert(n.children, i+1, right)
+		switch c := cmp.Compare(e.key, middle.key); {
+		case c == 0:
+			n.entries[i] = e
+			return true
+		case c > 0:
+			child = right
+		}
+	}
+	replaced := m.insert(child, e)
+	if !replaced {
+		n.size++
+	}
+	return replaced
+}
+
+type removal int
+
+const (
+	removeKey removal = iota
+	removeMax
+)
+
+// remove removes the entry for key, or the largest entry when what is
+// removeMax, from the subtree rooted at the mutable node n. Every node it
+// descends into is first given more than the minimum number of entries, so
+// that removing from it never leaves it underfull.
+func (m *OrderedMap[K, V]) remove(n *mapNode[K, V], key K, what removal) (mapEntry[K, V], bool) {
+	var i int
+	var found bool
+	if what == removeMax {
+		if n.leaf() {
+			i, found = len(n.entries)-1, true
+		} else {
+			i = len(n.entries)
+		}
+	} else {
+		i, found = n.find(key)
+	}
+
+	if n.leaf() {
+		if !found {
+			return mapEntry[K, V]{}, false
+		}
+		removed := n.entries[i]
+		n.entries = slices.Delete(n.entries, i, i+1)
+		n.size--
+		return removed, true
+	}
+
+	if len(n.children[i].entries) <= minNodeEntries {
+		m.growChild(n, i)
+		return m.remove(n, key, what)
+	}
+
+	child := m.mutableChild(n, i)
+	if found {
+		removed := n.entries[i]
+		n.entries[i], _ = m.remove(child, key, removeMax)
+		n.size--
+		return removed, true
+	}
+	removed, ok := m.remove(child, key, what)
+	if ok {
+		n.size--
+	}
+	return removed, ok
+}
+
+// growChild gives the i-th child of the mutable node n one more entry, by
+// rotating one from a sibling or merging it with a sibling.
+func (m *OrderedMap[K, V]) growChild(n *mapNode[K, V], i int) {
+	switch {
+	case i > 0 && len(n.children[i-1].entries) > minNodeEntries:
+		child := m.mutableChild(n, i)
+		left := m.mutableChild(n, i-1)
+		stolen := left.entries[len(left.entries)-1]
+		left.entries = left.entries[:len(left.entries)-1]
+		child.entries = slices.Insert(child.entries, 0, n.entries[i-1])
+		n.entries[i-1] = stolen
+		moved := 1
+		if !left.leaf() {
+			grandchild := left.children[len(left.children)-1]
+			left.children = left.children[:len(left.children)-1]
+			child.children = slices.Insert(child.children, 0, grandchild)
+			moved += grandchild.size
+		}
+		left.size -= moved
+		child.size += moved
+
+	case i < len(n.entries) && len(n.children[i+1].entries) > minNodeEntries:
+		child := m.mutableChild(n, i)
+		right := m.mutableChild(n, i+1)
+		stolen := right.entries[0]
+		right.entries = slices.Delete(right.entries, 0, 1)
+		child.entries = append(child.entries, n.entries[i])
+		n.entries[i] = stolen
+		moved := 1
+		if !right.leaf() {
+			grandchild := right.children[0]
+			right.children = slices.Delete(right.children, 0, 1)
+			child.children = append(child.children, grandchild)
+			moved += grandchild.size
+		}
+		right.size -= moved
+		child.size += moved
+
+	default:
+		if i >= len(n.entries) {
+			i--
+		}
+		child := m.mutableChild(n, i)
+		sibling := n.children[i+1]
+		child.entries = append(child.entries, n.entries[i])
+		child.entries = append(child.entries, sibling.entries...)
+		child.children = append(child.children, sibling.children...)
+		child.size += 1 + sibling.size
+		n.entries = slices.Delete(n.entries, i, i+1)
+		n.children = slices.Delete(n.children, i+1, i+2)
+	}
+}
@@ -0,0 +1,252 @@
+package container
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkOrderedMap verifies the B-tree invariants of m and that it holds
+// exactly the entries of expected.
+func checkOrderedMap(t *testing.T, m *OrderedMap[int, int], expected map[int]int) {
+	t.Helper()
+	require.Equal(t, len(expected), m.Len())
+	if m.root != nil {
+		leafDepth := -1
+		var walk func(n *mapNode[int, int], depth int) int
+		walk = func(n *mapNode[int, int], depth int) int {
+			if n != m.root {
+				require.GreaterOrEqual(t, len(n.entries), minNodeEntries)
+			}
+			require.LessOrEqual(t, len(n.entries), maxNodeEntries)
+			require.NotEmpty(t, n.entries)
+			size := len(n.entries)
+			if n.leaf() {
+				if leafDepth < 0 {
+					leafDepth = depth
+				}
+				require.Equal(t, leafDepth, depth)
+			} else {
+				require.Len(t, n.children, len(n.entries)+1)
+				for _, child := range n.children {
+					size += walk(child, depth+1)
+				}
+			}
+			require.Equal(t, size, n.size)
+			return size
+		}
+		walk(m.root, 0)
+	}
+
+	keys := slices.Sorted(maps.Keys(expected))
+	require.Equal(t, keys, slices.Collect(m.Keys()))
+	for k, v := range m.All() {
+		require.Equal(t, expected[k], v)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	t.Run("matches a map under random operations", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		m := NewOrderedMap[int, int]()
+		expected := make(map[int]int)
+		for i := range 20000 {
+			key := r.IntN(3000)
+			if r.IntN(3) == 0 {
+				_, ok := expected[key]
+				require.Equal(t, ok, m.Delete(key))
+				delete(expected, key)
+			} else {
+				_, ok := expected[key]
+				require.Equal(t, ok, m.Set(key, i))
+				expected[key] = i
+			}
+			if i%1000 == 0 {
+				checkOrderedMap(t, m, expected)
+			}
+		}
+		checkOrderedMap(t, m, expected)
+
+		for key := range 3000 {
+			v, ok := m.Get(key)
+			ev, eok := expected[key]
+			require.Equal(t, eok, ok)
+			require.Equal(t, ev, v)
+		}
+		for key := range expected {
+			require.True(t, m.Delete(key))
+		}
+		checkOrderedMap(t, m, map[int]int{})
+		_, _, ok := m.Min()
+		require.False(t, ok)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var m OrderedMap[string, int]
+		require.False(t, m.Delete("a"))
+		require.False(t, m.Set("a", 1))
+		require.True(t, m.Set("a", 2))
+		v, ok := m.Get("a")
+		require.True(t, ok)
+		require.Equal(t, 2, v)
+		m.Clear()
+		require.Zero(t, m.Len())
+	})
+
+	t.Run("ordered queries", func(t *testing.T) {
+		m := NewOrderedMap[int, string]()
+		for i := 0; i < 1000; i += 10 {
+			m.Set(i, "v")
+		}
+
+		k, _, ok := m.Min()
+		require.True(t, ok)
+		require.Equal(t, 0, k)
+		k, _, ok = m.Max()
+		require.True(t, ok)
+		require.Equal(t, 990, k)
+
+		k, _, ok = m.Floor(455)
+		require.True(t, ok)
+		require.Equal(t, 450, k)
+		k, _, ok = m.Floor(460)
+		require.True(t, ok)
+		require.Equal(t, 460, k)
+		_, _, ok = m.Floor(-1)
+		require.False(t, ok)
+
+		k, _, ok = m.Ceiling(455)
+		require.True(t, ok)
+		require.Equal(t, 460, k)
+		k, _, ok = m.Ceiling(-5)
+		require.True(t, ok)
+		require.Equal(t, 0, k)
+		_, _, ok = m.Ceiling(991)
+		require.False(t, ok)
+
+		var keys []int
+		for k := range m.Range(95, 150) {
+			keys = append(keys, k)
+		}
+		require.Equal(t, []int{100, 110, 120, 130, 140}, keys)
+		for range m.Range(500, 500) {
+			t.Fatal("empty range yielded an entry")
+		}
+
+		keys = keys[:0]
+		for k := range m.Backward() {
+			keys = append(keys, k)
+			if len(keys) == 3 {
+				break
+			}
+		}
+		require.Equal(t, []int{990, 980, 970}, keys)
+	})
+
+	t.Run("rank and select", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(3, 4))
+		m := NewOrderedMap[int, int]()
+		for range 5000 {
+			k := r.IntN(100000)
+			m.Set(k, -k)
+		}
+		keys := slices.Collect(m.Keys())
+
+		for i, key := range keys {
+			require.Equal(t, i, m.Rank(key))
+			k, v, ok := m.Select(i)
+			require.True(t, ok)
+			require.Equal(t, key, k)
+			require.Equal(t, -key, v)
+		}
+		require.Equal(t, 0, m.Rank(-1))
+		require.Equal(t, len(keys), m.Rank(100000))
+		_, found := slices.BinarySearch(keys, 50000)
+		require.False(t, found)
+		i, _ := slices.BinarySearch(keys, 50000)
+		require.Equal(t, i, m.Rank(50000))
+
+		_, _, ok := m.Select(-1)
+		require.False(t, ok)
+		_, _, ok = m.Select(len(keys))
+		require.False(t, ok)
+	})
+
+	t.Run("snapshots are isolated", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(5, 6))
+		m := NewOrderedMap[int, int]()
+		expected := make(map[int]int)
+		for i := range 2000 {
+			m.Set(i, i)
+			expected[i] = i
+		}
+
+		snapshot := m.Snapshot()
+		frozen := maps.Clone(expected)
+		for i := range 5000 {
+			key := r.IntN(4000)
+			if r.IntN(2) == 0 {
+				m.Delete(key)
+				delete(expected, key)
+			} else {
+				m.Set(key, -i)
+				expected[key] = -i
+			}
+		}
+		checkOrderedMap(t, m, expected)
+		checkOrderedMap(t, snapshot, frozen)
+
+		snapshot.Set(-1, -1)
+		frozen[-1] = -1
+		checkOrderedMap(t, m, expected)
+		checkOrderedMap(t, snapshot, frozen)
+	})
+
+	t.Run("readers run concurrently with a writer", func(t *testing.T) {
+		m := NewOrderedMap[int, int]()
+		for i := range 1000 {
+			m.Set(i, i)
+		}
+
+		var wg sync.WaitGroup
+		for round := range 8 {
+			snapshot := m.Snapshot()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				count := 0
+				for k, v := range snapshot.All() {
+					if k != v-round {
+						t.Errorf("round %d: got %d for key %d", round, v, k)
+						return
+					}
+					count++
+				}
+				if count != 1000 {
+					t.Errorf("round %d: got %d entries", round, count)
+				}
+			}()
+			for i := range 1000 {
+				m.Set(i, i+round+1)
+			}
+		}
+		wg.Wait()
+	})
+}
+
+func BenchmarkOrderedMapSet(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 1))
+	keys := make([]int, 1<<16)
+	for i := range keys {
+		keys[i] = r.Int()
+	}
+	m := NewOrderedMap[int, int]()
+	b.ResetTimer()
+	for i := range b.N {
+		m.Set(keys[i%len(keys)], i)
+	}
+}
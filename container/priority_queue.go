@@ -0,0 +1,190 @@
+package container
+
+import "iter"
+
+// Handle refers to an element pushed onto a PriorityQueue, allowing it to be
+// updated or removed later. The methods of a Handle obtained from a
+// SyncPriorityQueue must not be called concurrently with operations on that
+// queue.
+type Handle[T any] struct {
+	value T
+	// index is the position of the handle in the heap, or -1 once it has
+	// been popped or removed.
+	index int
+}
+
+// Value returns the element the handle refers to.
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// Queued reports whether the element is still in its queue.
+func (h *Handle[T]) Queued() bool {
+	return h.index >= 0
+}
+
+// PriorityQueue is a binary heap whose head is the smallest element according
+// to a less function. Pushing, popping, updating and removing an element run
+// in O(log n) time.
+type PriorityQueue[T any] struct {
+	heap []*Handle[T]
+	less func(a, b T) bool
+}
+
+// NewPriorityQueue creates an empty PriorityQueue ordered by less: the element
+// for which less reports true against every other one is popped first.
+//
+// Example:
+//
+//	jobs := container.NewPriorityQueue(func(a, b Job) bool { return a.Deadline.Before(b.Deadline) })
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// Len returns the number of elements in the queue.
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.heap)
+}
+
+// Push adds element to the queue and returns its handle.
+func (q *PriorityQueue[T]) Push(element T) *Handle[T] {
+	h := &Handle[T]{value: element, index: len(q.heap)}
+	q.heap = append(q.heap, h)
+	q.up(h.index)
+	return h
+}
+
+// Pop removes and returns the smallest element of the queue. The boolean
+// result is false if the queue is empty.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.heap) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.removeAt(0), true
+}
+
+// Peek returns the smallest element of the queue without removing it. The
+// boolean result is false if the queue is empty.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.heap) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.heap[0].value, true
+}
+
+// Update replaces the element referred to by h with element and restores the
+// ordering of the queue. It returns false, leaving the queue untouched, if h
+// does not refer to an element of q.
+func (q *PriorityQueue[T]) Update(h *Handle[T], element T) bool {
+	if !q.owns(h) {
+		return false
+	}
+	h.value = element
+	if !q.down(h.index) {
+		q.up(h.index)
+	}
+	return true
+}
+
+// Remove removes the element referred to by h from the queue and returns it.
+// The boolean result is false if h does not refer to an element of q.
+func (q *PriorityQueue[T]) Remove(h *Handle[T]) (T, bool) {
+	if !q.owns(h) {
+		var zero T
+		return zero, false
+	}
+	return q.removeAt(h.index), true
+}
+
+// Clear removes all elements from the queue.
+func (q *PriorityQueue[T]) Clear() {
+	for _, h := range q.heap {
+		h.index = -1
+	}
+	clear(q.heap)
+	q.heap = q.heap[:0]
+}
+
+// All returns an iterator over the elements of the queue in heap order, which
+// is not sorted beyond the first element. The queue must not be modified
+// during the iteration.
+func (q *PriorityQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, h := range q.heap {
+			if !yield(h.value) {
+				return
+			}
+		}
+	}
+}
+
+// Drain returns an iterator popping the elements of the queue in priority
+// order until it is empty or the iteration stops.
+func (q *PriorityQueue[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for len(q.heap) > 0 {
+			if !yield(q.removeAt(0)) {
+				return
+			}
+		}
+	}
+}
+
+func (q *PriorityQueue[T]) owns(h *Handle[T]) bool {
+	return h != nil && h.index >= 0 && h.index < len(q.heap) && q.heap[h.index] == h
+}
+
+func (q *PriorityQueue[T]) removeAt(i int) T {
+	h := q.heap[i]
+	last := len(q.heap) - 1
+	if i != last {
+		q.swap(i, last)
+	}
+	q.heap[last] = nil
+	q.heap = q.heap[:last]
+	if i != last && !q.down(i) {
+		q.up(i)
+	}
+	h.index = -1
+	return h.value
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.heap[i], q.heap[j] = q.heap[j], q.heap[i]
+	q.heap[i].index = i
+	q.heap[j].index = j
+}
+
+func (q *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.heap[i].value, q.heap[parent].value) {
+			return
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the element at i towards the leaves and reports whether it
+// moved.
+func (q *PriorityQueue[T]) down(i int) bool {
+	start := i
+	for {
+		smallest := 2*i + 1
+		if smallest >= len(q.heap) {
+			break
+		}
+		if right := smallest + 1; right < len(q.heap) && q.less(q.heap[right].value, q.heap[smallest].value) {
+			smallest = right
+		}
+		if !q.less(q.heap[smallest].value, q.heap[i].value) {
+			break
+		}
+		q.swap(i, smallest)
+		i = smallest
+	}
+	return i > start
+}
@@ -0,0 +1,96 @@
+package container
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Run("pops in order", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 1))
+		q := NewPriorityQueue(intLess)
+		expected := make([]int, 200)
+		for i := range expected {
+			expected[i] = r.IntN(1000)
+			q.Push(expected[i])
+		}
+		slices.Sort(expected)
+
+		require.Equal(t, len(expected), q.Len())
+		require.ElementsMatch(t, expected, slices.Collect(q.All()))
+		head, ok := q.Peek()
+		require.True(t, ok)
+		require.Equal(t, expected[0], head)
+		require.Equal(t, expected, slices.Collect(q.Drain()))
+
+		_, ok = q.Pop()
+		require.False(t, ok)
+	})
+
+	t.Run("update and remove by handle", func(t *testing.T) {
+		q := NewPriorityQueue(intLess)
+		handles := make([]*Handle[int], 10)
+		for i := range handles {
+			handles[i] = q.Push(i * 10)
+		}
+
+		require.True(t, q.Update(handles[9], -1))
+		require.True(t, q.Update(handles[0], 55))
+		v, ok := q.Remove(handles[5])
+		require.True(t, ok)
+		require.Equal(t, 50, v)
+		require.False(t, handles[5].Queued())
+
+		_, ok = q.Remove(handles[5])
+		require.False(t, ok)
+		require.False(t, q.Update(handles[5], 0))
+
+		other := NewPriorityQueue(intLess)
+		_, ok = other.Remove(handles[1])
+		require.False(t, ok)
+
+		require.Equal(t, []int{-1, 10, 20, 30, 40, 55, 60, 70, 80}, slices.Collect(q.Drain()))
+		require.False(t, handles[0].Queued())
+		require.Equal(t, 55, handles[0].Value())
+	})
+
+	t.Run("random operations keep the heap ordered", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(2, 2))
+		q := NewPriorityQueue(intLess)
+		var live []*Handle[int]
+		for range 2000 {
+			switch op := r.IntN(4); {
+			case op == 0 || len(live) == 0:
+				live = append(live, q.Push(r.IntN(100)))
+			case op == 1:
+				h := live[r.IntN(len(live))]
+				require.True(t, q.Update(h, r.IntN(100)))
+			case op == 2:
+				i := r.IntN(len(live))
+				_, ok := q.Remove(live[i])
+				require.True(t, ok)
+				live = slices.Delete(live, i, i+1)
+			default:
+				v, _ := q.Pop()
+				for _, h := range live {
+					require.GreaterOrEqual(t, h.Value(), v)
+				}
+				live = slices.DeleteFunc(live, func(h *Handle[int]) bool { return !h.Queued() })
+			}
+			require.Equal(t, len(live), q.Len())
+		}
+
+		q.Clear()
+		require.Equal(t, 0, q.Len())
+		for _, h := range live {
+			require.False(t, h.Queued())
+		}
+	})
+}
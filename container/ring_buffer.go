@@ -0,0 +1,114 @@
+package container
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrFull is returned by RingBuffer.Push when the buffer is full and its
+// overflow mode is RejectNewest.
+var ErrFull = errors.New("container: ring buffer is full")
+
+// OverflowMode selects what a RingBuffer does when an element is pushed while
+// it is full.
+type OverflowMode int
+
+const (
+	// OverwriteOldest evicts the oldest element to make room for the new
+	// one.
+	OverwriteOldest OverflowMode = iota
+	// RejectNewest keeps the buffer untouched and rejects the new element.
+	RejectNewest
+)
+
+// RingBuffer is a first-in first-out queue with a fixed capacity.
+type RingBuffer[T any] struct {
+	buffer []T
+	head   int
+	length int
+	mode   OverflowMode
+}
+
+// NewRingBuffer creates an empty RingBuffer holding at most capacity elements
+// and handling overflows according to mode. It panics if capacity is less
+// than 1.
+func NewRingBuffer[T any](capacity int, mode OverflowMode) *RingBuffer[T] {
+	if capacity < 1 {
+		panic("container: ring buffer capacity must be at least 1")
+	}
+	return &RingBuffer[T]{buffer: make([]T, capacity), mode: mode}
+}
+
+// Len returns the number of elements in the buffer.
+func (r *RingBuffer[T]) Len() int {
+	return r.length
+}
+
+// Cap returns the capacity of the buffer.
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.buffer)
+}
+
+// Full reports whether the buffer holds as many elements as its capacity.
+func (r *RingBuffer[T]) Full() bool {
+	return r.length == len(r.buffer)
+}
+
+// Push appends element to the buffer. When the buffer is full, it either
+// evicts the oldest element or returns ErrFull, depending on the overflow
+// mode of the buffer.
+func (r *RingBuffer[T]) Push(element T) error {
+	if r.Full() {
+		if r.mode == RejectNewest {
+			return ErrFull
+		}
+		r.buffer[r.head] = element
+		r.head = (r.head + 1) % len(r.buffer)
+		return nil
+	}
+	r.buffer[(r.head+r.length)%len(r.buffer)] = element
+	r.length++
+	return nil
+}
+
+// Pop removes and returns the oldest element of the buffer. The boolean
+// result is false if the buffer is empty.
+func (r *RingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	if r.length == 0 {
+		return zero, false
+	}
+	element := r.buffer[r.head]
+	r.buffer[r.head] = zero
+	r.head = (r.head + 1) % len(r.buffer)
+	r.length--
+	return element, true
+}
+
+// Peek returns the oldest element of the buffer without removing it. The
+// boolean result is false if the buffer is empty.
+func (r *RingBuffer[T]) Peek() (T, bool) {
+	if r.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.buffer[r.head], true
+}
+
+// Clear removes all elements from the buffer.
+func (r *RingBuffer[T]) Clear() {
+	clear(r.buffer)
+	r.head, r.length = 0, 0
+}
+
+// All returns an iterator over the elements of the buffer, oldest first. The
+// buffer must not be modified during the iteration.
+func (r *RingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range r.length {
+			if !yield(r.buffer[(r.head+i)%len(r.buffer)]) {
+				return
+			}
+		}
+	}
+}
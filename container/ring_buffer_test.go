@@ -0,0 +1,53 @@
+package container
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBuffer(t *testing.T) {
+	t.Run("overwrite oldest", func(t *testing.T) {
+		r := NewRingBuffer[int](3, OverwriteOldest)
+		for i := range 5 {
+			require.NoError(t, r.Push(i))
+		}
+		require.True(t, r.Full())
+		require.Equal(t, 3, r.Len())
+		require.Equal(t, 3, r.Cap())
+		require.Equal(t, []int{2, 3, 4}, slices.Collect(r.All()))
+
+		oldest, ok := r.Peek()
+		require.True(t, ok)
+		require.Equal(t, 2, oldest)
+
+		for _, expected := range []int{2, 3, 4} {
+			v, ok := r.Pop()
+			require.True(t, ok)
+			require.Equal(t, expected, v)
+		}
+		_, ok = r.Pop()
+		require.False(t, ok)
+	})
+
+	t.Run("reject newest", func(t *testing.T) {
+		r := NewRingBuffer[string](2, RejectNewest)
+		require.NoError(t, r.Push("a"))
+		require.NoError(t, r.Push("b"))
+		require.ErrorIs(t, r.Push("c"), ErrFull)
+		require.Equal(t, []string{"a", "b"}, slices.Collect(r.All()))
+
+		r.Pop()
+		require.NoError(t, r.Push("c"))
+		require.Equal(t, []string{"b", "c"}, slices.Collect(r.All()))
+
+		r.Clear()
+		_, ok := r.Peek()
+		require.False(t, ok)
+	})
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		require.Panics(t, func() { NewRingBuffer[int](0, OverwriteOldest) })
+	})
+}
@@ -0,0 +1,254 @@
+package container
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncDeque is a Deque safe for concurrent use.
+//
+// The zero value of SyncDeque is an empty deque ready to use.
+type SyncDeque[T any] struct {
+	mu    sync.Mutex
+	deque Deque[T]
+}
+
+// NewSyncDeque creates a new SyncDeque holding the given elements, front to
+// back.
+func NewSyncDeque[T any](elements ...T) *SyncDeque[T] {
+	return &SyncDeque[T]{deque: *NewDeque(elements...)}
+}
+
+// Len returns the number of elements in the deque.
+func (d *SyncDeque[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deque.Len()
+}
+
+// PushBack adds element at the back of the deque.
+func (d *SyncDeque[T]) PushBack(element T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deque.PushBack(element)
+}
+
+// PushFront adds element at the front of the deque.
+func (d *SyncDeque[T]) PushFront(element T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deque.PushFront(element)
+}
+
+// PopFront removes and returns the element at the front of the deque. The
+// boolean result is false if the deque is empty.
+func (d *SyncDeque[T]) PopFront() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deque.PopFront()
+}
+
+// PopBack removes and returns the element at the back of the deque. The
+// boolean result is false if the deque is empty.
+func (d *SyncDeque[T]) PopBack() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deque.PopBack()
+}
+
+// Front returns the element at the front of the deque without removing it.
+// The boolean result is false if the deque is empty.
+func (d *SyncDeque[T]) Front() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deque.Front()
+}
+
+// Back returns the element at the back of the deque without removing it. The
+// boolean result is false if the deque is empty.
+func (d *SyncDeque[T]) Back() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deque.Back()
+}
+
+// Clear removes all elements from the deque.
+func (d *SyncDeque[T]) Clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deque.Clear()
+}
+
+// All returns an iterator over a snapshot of the elements of the deque, front
+// to back, taken when the iteration starts. The deque may be modified during
+// the iteration.
+func (d *SyncDeque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		d.mu.Lock()
+		snapshot := slices.Collect(d.deque.All())
+		d.mu.Unlock()
+		yieldAll(snapshot, yield)
+	}
+}
+
+// SyncRingBuffer is a RingBuffer safe for concurrent use.
+type SyncRingBuffer[T any] struct {
+	mu     sync.Mutex
+	buffer *RingBuffer[T]
+}
+
+// NewSyncRingBuffer creates an empty SyncRingBuffer holding at most capacity
+// elements and handling overflows according to mode. It panics if capacity
+// is less than 1.
+func NewSyncRingBuffer[T any](capacity int, mode OverflowMode) *SyncRingBuffer[T] {
+	return &SyncRingBuffer[T]{buffer: NewRingBuffer[T](capacity, mode)}
+}
+
+// Len returns the number of elements in the buffer.
+func (r *SyncRingBuffer[T]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buffer.Len()
+}
+
+// Cap returns the capacity of the buffer.
+func (r *SyncRingBuffer[T]) Cap() int {
+	return r.buffer.Cap()
+}
+
+// Full reports whether the buffer holds as many elements as its capacity.
+func (r *SyncRingBuffer[T]) Full() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buffer.Full()
+}
+
+// Push appends element to the buffer. When the buffer is full, it either
+// evicts the oldest element or returns ErrFull, depending on the overflow
+// mode of the buffer.
+func (r *SyncRingBuffer[T]) Push(element T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buffer.Push(element)
+}
+
+// Pop removes and returns the oldest element of the buffer. The boolean
+// result is false if the buffer is empty.
+func (r *SyncRingBuffer[T]) Pop() (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buffer.Pop()
+}
+
+// Peek returns the oldest element of the buffer without removing it. The
+// boolean result is false if the buffer is empty.
+func (r *SyncRingBuffer[T]) Peek() (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buffer.Peek()
+}
+
+// Clear removes all elements from the buffer.
+func (r *SyncRingBuffer[T]) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buffer.Clear()
+}
+
+// All returns an iterator over a snapshot of the elements of the buffer,
+// oldest first, taken when the iteration starts. The buffer may be modified
+// during the iteration.
+func (r *SyncRingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		r.mu.Lock()
+		snapshot := slices.Collect(r.buffer.All())
+		r.mu.Unlock()
+		yieldAll(snapshot, yield)
+	}
+}
+
+// SyncPriorityQueue is a PriorityQueue safe for concurrent use.
+type SyncPriorityQueue[T any] struct {
+	mu    sync.Mutex
+	queue *PriorityQueue[T]
+}
+
+// NewSyncPriorityQueue creates an empty SyncPriorityQueue ordered by less.
+func NewSyncPriorityQueue[T any](less func(a, b T) bool) *SyncPriorityQueue[T] {
+	return &SyncPriorityQueue[T]{queue: NewPriorityQueue(less)}
+}
+
+// Len returns the number of elements in the queue.
+func (q *SyncPriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Len()
+}
+
+// Push adds element to the queue and returns its handle.
+func (q *SyncPriorityQueue[T]) Push(element T) *Handle[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Push(element)
+}
+
+// Pop removes and returns the smallest element of the queue. The boolean
+// result is false if the queue is empty.
+func (q *SyncPriorityQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Pop()
+}
+
+// Peek returns the smallest element of the queue without removing it. The
+// boolean result is false if the queue is empty.
+func (q *SyncPriorityQueue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Peek()
+}
+
+// Update replaces the element referred to by h with element and restores the
+// ordering of the queue. It returns false if h does not refer to an element
+// of q.
+func (q *SyncPriorityQueue[T]) Update(h *Handle[T], element T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Update(h, element)
+}
+
+// Remove removes the element referred to by h from the queue and returns it.
+// The boolean result is false if h does not refer to an element of q.
+func (q *SyncPriorityQueue[T]) Remove(h *Handle[T]) (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Remove(h)
+}
+
+// Clear removes all elements from the queue.
+func (q *SyncPriorityQueue[T]) Clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queue.Clear()
+}
+
+// All returns an iterator over a snapshot of the elements of the queue in
+// heap order, taken when the iteration starts. The queue may be modified
+// during the iteration.
+func (q *SyncPriorityQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		q.mu.Lock()
+		snapshot := slices.Collect(q.queue.All())
+		q.mu.Unlock()
+		yieldAll(snapshot, yield)
+	}
+}
+
+func yieldAll[T any](elements []T, yield func(T) bool) {
+	for _, element := range elements {
+		if !yield(element) {
+			return
+		}
+	}
+}
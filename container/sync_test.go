@@ -0,0 +1,80 @@
+package container
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncContainers(t *testing.T) {
+	const workers, perWorker = 8, 500
+
+	t.Run("deque", func(t *testing.T) {
+		var d SyncDeque[int]
+		var wg sync.WaitGroup
+		for w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range perWorker {
+					if w%2 == 0 {
+						d.PushBack(i)
+					} else {
+						d.PushFront(i)
+					}
+					if i%2 == 0 {
+						d.PopFront()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, workers*perWorker/2, d.Len())
+		require.Len(t, slices.Collect(d.All()), workers*perWorker/2)
+	})
+
+	t.Run("ring buffer", func(t *testing.T) {
+		r := NewSyncRingBuffer[int](100, OverwriteOldest)
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range perWorker {
+					require.NoError(t, r.Push(i))
+				}
+			}()
+		}
+		wg.Wait()
+		require.True(t, r.Full())
+		require.Len(t, slices.Collect(r.All()), 100)
+	})
+
+	t.Run("priority queue", func(t *testing.T) {
+		q := NewSyncPriorityQueue(intLess)
+		var wg sync.WaitGroup
+		for w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range perWorker {
+					h := q.Push(w*perWorker + i)
+					if i%2 == 0 {
+						q.Update(h, -(w*perWorker + i))
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, workers*perWorker, q.Len())
+
+		previous, _ := q.Pop()
+		for q.Len() > 0 {
+			v, _ := q.Pop()
+			require.GreaterOrEqual(t, v, previous)
+			previous = v
+		}
+	})
+}
@@ -0,0 +1,329 @@
+package container
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Trie maps string keys to values and answers prefix queries. By default it
+// splits keys into bytes; a segment trie created by NewSegmentTrie splits
+// them into the segments delimited by a separator, so that prefixes only
+// match whole segments.
+//
+// Each entry carries a weight, used by Complete to rank completions. A Trie
+// must be created with NewTrie or NewSegmentTrie.
+type Trie[V any] struct {
+	separator string
+	root      *trieNode[V]
+	length    int
+}
+
+type trieNode[V any] struct {
+	label    string
+	value    V
+	weight   float64
+	hasValue bool
+	// children is sorted by label.
+	children []*trieNode[V]
+	// maxWeight is the largest weight of the entries in the subtree rooted
+	// at the node.
+	maxWeight float64
+}
+
+// Completion is an entry of a Trie returned by Complete.
+type Completion[V any] struct {
+	Key    string
+	Value  V
+	Weight float64
+}
+
+// NewTrie creates an empty Trie splitting keys into bytes.
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{root: &trieNode[V]{}}
+}
+
+// NewSegmentTrie creates an empty Trie splitting keys into the segments
+// delimited by separator. It panics if separator is empty.
+//
+// Example:
+//
+//	keys := container.NewSegmentTrie[int](":")
+//	keys.Insert(gobag.GenRedisKey("user", "42", "name"), 1)
+//	for key := range keys.WalkPrefix("user:42:") {
+//		fmt.Println(key)
+//	}
+func NewSegmentTrie[V any](separator string) *Trie[V] {
+	if separator == "" {
+		panic("container: trie separator must not be empty")
+	}
+	return &Trie[V]{separator: separator, root: &trieNode[V]{}}
+}
+
+// Len returns the number of entries in the trie.
+func (t *Trie[V]) Len() int {
+	return t.length
+}
+
+// Insert stores value for key with a weight of zero and reports whether it
+// replaced an existing entry.
+func (t *Trie[V]) Insert(key string, value V) bool {
+	return t.InsertWeighted(key, value, 0)
+}
+
+// InsertWeighted stores value for key with the given weight and reports
+// whether it replaced an existing entry.
+func (t *Trie[V]) InsertWeighted(key string, value V, weight float64) bool {
+	path := []*trieNode[V]{t.root}
+	n := t.root
+	for _, label := range t.split(key) {
+		i, found := n.find(label)
+		if !found {
+			n.children = slices.Insert(n.children, i, &trieNode[V]{label: label})
+		}
+		n = n.children[i]
+		path = append(path, n)
+	}
+
+	replaced := n.hasValue
+	if !replaced {
+		t.length++
+	}
+	n.value, n.weight, n.hasValue = value, weight, true
+	updateMaxWeights(path)
+	return replaced
+}
+
+// Get returns the value stored for key. The boolean result is false if there
+// is no such entry.
+func (t *Trie[V]) Get(key string) (V, bool) {
+	n := t.root
+	for _, label := range t.split(key) {
+		if n = n.child(label); n == nil {
+			var zero V
+			return zero, false
+		}
+	}
+	return n.value, n.hasValue
+}
+
+// Delete removes the entry stored for key and reports whether there was one.
+func (t *Trie[V]) Delete(key string) bool {
+	path := []*trieNode[V]{t.root}
+	n := t.root
+	for _, label := range t.split(key) {
+		if n = n.child(label); n == nil {
+			return false
+		}
+		path = append(path, n)
+	}
+	if !n.hasValue {
+		return false
+	}
+
+	var zero V
+	n.value, n.weight, n.hasValue = zero, 0, false
+	t.length--
+	// Prune the nodes left without entries.
+	for len(path) > 1 {
+		last := path[len(path)-1]
+		if last.hasValue || len(last.children) > 0 {
+			break
+		}
+		parent := path[len(path)-2]
+		i, _ := parent.find(last.label)
+		parent.children = slices.Delete(parent.children, i, i+1)
+		path = path[:len(path)-1]
+	}
+	updateMaxWeights(path)
+	return true
+}
+
+// WalkPrefix returns an iterator over the entries whose key starts with
+// prefix, in lexicographic order of their bytes, or of their segments for a
+// segment trie. In a segment trie, prefix must consist of whole segments and
+// may end with the separator: "user:42" and "user:42:" both match
+// "user:42:name" but not "user:420". The trie must not be modified during the
+// iteration.
+func (t *Trie[V]) WalkPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		labels := t.splitPrefix(prefix)
+		n := t.root
+		for _, label := range labels {
+			if n = n.child(label); n == nil {
+				return
+			}
+		}
+		t.walk(n, labels, yield)
+	}
+}
+
+// All returns an iterator over the entries of the trie in the order of
+// WalkPrefix. The trie must not be modified during the iteration.
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return t.WalkPrefix("")
+}
+
+// LongestPrefixMatch returns the entry with the longest key that is a prefix
+// of key, made of whole segments for a segment trie. The boolean result is
+// false if there is none.
+func (t *Trie[V]) LongestPrefixMatch(key string) (string, V, bool) {
+	labels := t.split(key)
+	var best *trieNode[V]
+	depth := 0
+	n := t.root
+	if n.hasValue {
+		best = n
+	}
+	for i, label := range labels {
+		if n = n.child(label); n == nil {
+			break
+		}
+		if n.hasValue {
+			best, depth = n, i+1
+		}
+	}
+	if best == nil {
+		var zero V
+		return "", zero, false
+	}
+	return strings.Join(labels[:depth], t.separator), best.value, true
+}
+
+// Complete returns at most n entries whose key starts with prefix, matched
+// as by WalkPrefix, ranked by decreasing weight and then by key. It explores
+// the trie best first, skipping the subtrees whose entries cannot make the
+// cut.
+func (t *Trie[V]) Complete(prefix string, n int) []Completion[V] {
+	if n < 1 {
+		return nil
+	}
+	labels := t.splitPrefix(prefix)
+	node := t.root
+	for _, label := range labels {
+		if node = node.child(label); node == nil {
+			return nil
+		}
+	}
+	if !node.hasValue && len(node.children) == 0 {
+		return nil
+	}
+
+	// The queue holds both entries and subtrees, a subtree being ranked by
+	// the largest weight and smallest key it could yield.
+	type candidate struct {
+		node  *trieNode[V]
+		key   string
+		entry bool
+	}
+	rank := func(c candidate) float64 {
+		if c.entry {
+			return c.node.weight
+		}
+		return c.node.maxWeight
+	}
+	queue := NewPriorityQueue(func(a, b candidate) bool {
+		if c := cmp.Compare(rank(b), rank(a)); c != 0 {
+			return c < 0
+		}
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		return a.entry && !b.entry
+	})
+	queue.Push(candidate{node: node, key: strings.Join(labels, t.separator)})
+
+	var completions []Completion[V]
+	for len(completions) < n {
+		c, ok := queue.Pop()
+		if !ok {
+			break
+		}
+		if c.entry {
+			completions = append(completions, Completion[V]{Key: c.key, Value: c.node.value, Weight: c.node.weight})
+			continue
+		}
+		if c.node.hasValue {
+			queue.Push(candidate{node: c.node, key: c.key, entry: true})
+		}
+		for _, child := range c.node.children {
+			queue.Push(candidate{node: child, key: t.join(c.key, child.label, c.node == t.root)})
+		}
+	}
+	return completions
+}
+
+func (t *Trie[V]) walk(n *trieNode[V], labels []string, yield func(string, V) bool) bool {
+	if n.hasValue && !yield(strings.Join(labels, t.separator), n.value) {
+		return false
+	}
+	for _, child := range n.children {
+		if !t.walk(child, append(labels, child.label), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// split returns the labels of the path leading to key.
+func (t *Trie[V]) split(key string) []string {
+	if t.separator != "" {
+		return strings.Split(key, t.separator)
+	}
+	labels := make([]string, len(key))
+	for i := range key {
+		labels[i] = key[i : i+1]
+	}
+	return labels
+}
+
+// splitPrefix returns the labels of the path leading to the entries whose key
+// starts with prefix.
+func (t *Trie[V]) splitPrefix(prefix string) []string {
+	if t.separator == "" {
+		return t.split(prefix)
+	}
+	if prefix == "" {
+		return nil
+	}
+	return t.split(strings.TrimSuffix(prefix, t.separator))
+}
+
+// join returns the key of the child labeled label of the node keyed key.
+func (t *Trie[V]) join(key, label string, root bool) string {
+	if root {
+		return label
+	}
+	return key + t.separator + label
+}
+
+func (n *trieNode[V]) find(label string) (int, bool) {
+	return slices.BinarySearchFunc(n.children, label, func(child *trieNode[V], label string) int {
+		return strings.Compare(child.label, label)
+	})
+}
+
+func (n *trieNode[V]) child(label string) *trieNode[V] {
+	if i, found := n.find(label); found {
+		return n.children[i]
+	}
+	return nil
+}
+
+// updateMaxWeights recomputes the largest weight of the subtrees rooted at
+// the nodes of path, from the deepest one up.
+func updateMaxWeights[V any](path []*trieNode[V]) {
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		first := true
+		if n.hasValue {
+			n.maxWeight, first = n.weight, false
+		}
+		for _, child := range n.children {
+			if first || child.maxWeight > n.maxWeight {
+				n.maxWeight, first = child.maxWeight, false
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package container
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectKeys[V any](seq func(yield func(string, V) bool)) []string {
+	var keys []string
+	for key := range seq {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestTrie(t *testing.T) {
+	t.Run("insert get and delete", func(t *testing.T) {
+		trie := NewTrie[int]()
+		require.False(t, trie.Insert("tea", 1))
+		require.False(t, trie.Insert("ten", 2))
+		require.False(t, trie.Insert("", 3))
+		require.True(t, trie.Insert("tea", 4))
+		require.Equal(t, 3, trie.Len())
+
+		v, ok := trie.Get("tea")
+		require.True(t, ok)
+		require.Equal(t, 4, v)
+		v, ok = trie.Get("")
+		require.True(t, ok)
+		require.Equal(t, 3, v)
+		_, ok = trie.Get("te")
+		require.False(t, ok)
+		_, ok = trie.Get("teapot")
+		require.False(t, ok)
+
+		require.False(t, trie.Delete("te"))
+		require.True(t, trie.Delete("tea"))
+		require.False(t, trie.Delete("tea"))
+		require.Equal(t, []string{"", "ten"}, collectKeys(trie.All()))
+		require.Len(t, trie.root.children, 1)
+		require.True(t, trie.Delete("ten"))
+		require.Empty(t, trie.root.children)
+	})
+
+	t.Run("walk prefix", func(t *testing.T) {
+		trie := NewTrie[int]()
+		for i, key := range []string{"car", "cart", "carbon", "cat", "dog", "ca"} {
+			trie.Insert(key, i)
+		}
+		require.Equal(t, []string{"car", "carbon", "cart"}, collectKeys(trie.WalkPrefix("car")))
+		require.Equal(t, []string{"ca", "car", "carbon", "cart", "cat", "dog"}, collectKeys(trie.All()))
+		require.Empty(t, collectKeys(trie.WalkPrefix("cow")))
+
+		var first []string
+		for key := range trie.WalkPrefix("c") {
+			first = append(first, key)
+			if len(first) == 2 {
+				break
+			}
+		}
+		require.Equal(t, []string{"ca", "car"}, first)
+	})
+
+	t.Run("segments", func(t *testing.T) {
+		trie := NewSegmentTrie[int](":")
+		for i, key := range []string{"user:42", "user:42:name", "user:42:email", "user:420:name", "session:1"} {
+			trie.Insert(key, i)
+		}
+
+		expected := []string{"user:42", "user:42:email", "user:42:name"}
+		require.Equal(t, expected, collectKeys(trie.WalkPrefix("user:42")))
+		require.Equal(t, expected, collectKeys(trie.WalkPrefix("user:42:")))
+		require.Empty(t, collectKeys(trie.WalkPrefix("user:4")))
+		require.Len(t, collectKeys(trie.All()), 5)
+
+		key, v, ok := trie.LongestPrefixMatch("user:42:name:first")
+		require.True(t, ok)
+		require.Equal(t, "user:42:name", key)
+		require.Equal(t, 1, v)
+		key, _, ok = trie.LongestPrefixMatch("user:42:phone")
+		require.True(t, ok)
+		require.Equal(t, "user:42", key)
+		_, _, ok = trie.LongestPrefixMatch("user:4200")
+		require.False(t, ok)
+
+		require.Panics(t, func() { NewSegmentTrie[int]("") })
+	})
+
+	t.Run("longest prefix match", func(t *testing.T) {
+		trie := NewTrie[string]()
+		trie.Insert("10.", "a")
+		trie.Insert("10.1.", "b")
+		key, v, ok := trie.LongestPrefixMatch("10.1.2.3")
+		require.True(t, ok)
+		require.Equal(t, "10.1.", key)
+		require.Equal(t, "b", v)
+		_, _, ok = trie.LongestPrefixMatch("1")
+		require.False(t, ok)
+
+		trie.Insert("", "root")
+		key, v, ok = trie.LongestPrefixMatch("1")
+		require.True(t, ok)
+		require.Equal(t, "", key)
+		require.Equal(t, "root", v)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		trie := NewTrie[int]()
+		trie.InsertWeighted("go", 0, 5)
+		trie.InsertWeighted("golang", 1, 9)
+		trie.InsertWeighted("gopher", 2, 7)
+		trie.InsertWeighted("google", 3, 9)
+		trie.InsertWeighted("gone", 4, 1)
+		trie.InsertWeighted("java", 5, 100)
+
+		completions := trie.Complete("go", 3)
+		require.Equal(t, []Completion[int]{
+			{Key: "golang", Value: 1, Weight: 9},
+			{Key: "google", Value: 3, Weight: 9},
+			{Key: "gopher", Value: 2, Weight: 7},
+		}, completions)
+		require.Len(t, trie.Complete("", 10), 6)
+		require.Equal(t, "java", trie.Complete("", 1)[0].Key)
+		require.Nil(t, trie.Complete("rust", 3))
+		require.Nil(t, trie.Complete("go", 0))
+
+		// Lowering or deleting the heaviest entries updates the ranking.
+		trie.InsertWeighted("golang", 1, 0)
+		require.True(t, trie.Delete("google"))
+		keys := []string{}
+		for _, c := range trie.Complete("go", 2) {
+			keys = append(keys, c.Key)
+		}
+		require.Equal(t, []string{"gopher", "go"}, keys)
+	})
+
+	t.Run("complete matches sorting every entry", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(7, 8))
+		trie := NewSegmentTrie[int](":")
+		weights := make(map[string]float64)
+		for i := range 2000 {
+			key := "k:" + strconv.Itoa(r.IntN(20)) + ":" + strconv.Itoa(r.IntN(50))
+			weight := float64(r.IntN(30))
+			trie.InsertWeighted(key, i, weight)
+			weights[key] = weight
+		}
+
+		for _, prefix := range []string{"", "k", "k:3", "k:3:1"} {
+			var expected []string
+			for _, key := range slices.Sorted(maps.Keys(weights)) {
+				if prefix == "" || key == prefix || len(key) > len(prefix) && key[:len(prefix)+1] == prefix+":" {
+					expected = append(expected, key)
+				}
+			}
+			slices.SortStableFunc(expected, func(a, b string) int {
+				switch {
+				case weights[a] > weights[b]:
+					return -1
+				case weights[a] < weights[b]:
+					return 1
+				}
+				return 0
+			})
+			if len(expected) > 25 {
+				expected = expected[:25]
+			}
+
+			var got []string
+			for _, c := range trie.Complete(prefix, 25) {
+				require.Equal(t, weights[c.Key], c.Weight)
+				got = append(got, c.Key)
+			}
+			require.Equal(t, expected, got, "prefix %q", prefix)
+		}
+	})
+}
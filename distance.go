@@ -0,0 +1,154 @@
+package gobag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDistance is returned by ParseDistance when a string is not a
+// number followed by a known unit.
+var ErrInvalidDistance = errors.New("invalid distance")
+
+// Distance is a length, stored as a number of meters. Like time.Duration, it
+// is built by multiplying a unit constant, and converted to a unit with a
+// method, which keeps the unit explicit at every use:
+//
+//	d := 12.5 * gobag.Kilometer
+//	fmt.Println(d.Miles()) // 7.767...
+//	fmt.Println(d)         // 12.5km
+type Distance float64
+
+// Common distances. To count the number of units in a Distance, divide:
+//
+//	fmt.Print(d / gobag.Foot)
+//
+// or use one of the conversion methods such as Feet.
+const (
+	Millimeter   Distance = 0.001
+	Centimeter   Distance = 0.01
+	Meter        Distance = 1
+	Kilometer    Distance = 1000
+	Foot         Distance = 0.3048
+	Mile         Distance = 1609.344
+	NauticalMile Distance = 1852
+)
+
+// Meters returns the distance as a number of meters.
+func (d Distance) Meters() float64 {
+	return float64(d)
+}
+
+// Kilometers returns the distance as a number of kilometers.
+func (d Distance) Kilometers() float64 {
+	return float64(d / Kilometer)
+}
+
+// Miles returns the distance as a number of international statute miles.
+func (d Distance) Miles() float64 {
+	return float64(d / Mile)
+}
+
+// NauticalMiles returns the distance as a number of international nautical
+// miles.
+func (d Distance) NauticalMiles() float64 {
+	return float64(d / NauticalMile)
+}
+
+// Feet returns the distance as a number of international feet.
+func (d Distance) Feet() float64 {
+	return float64(d / Foot)
+}
+
+// String returns the distance in the metric unit best suited to its size:
+// millimeters below a meter, kilometers from a kilometer, and meters in
+// between, such as "350mm", "42.195km" or "0m". The result of a finite
+// distance can be parsed back with ParseDistance.
+func (d Distance) String() string {
+	abs := math.Abs(float64(d))
+	switch {
+	case abs == 0 || math.IsNaN(abs) || math.IsInf(abs, 0):
+		return strconv.FormatFloat(float64(d), 'f', -1, 64) + "m"
+	case abs < 1:
+		return strconv.FormatFloat(float64(d/Millimeter), 'f', -1, 64) + "mm"
+	case abs < 1000:
+		return strconv.FormatFloat(float64(d), 'f', -1, 64) + "m"
+	default:
+		return strconv.FormatFloat(float64(d/Kilometer), 'f', -1, 64) + "km"
+	}
+}
+
+// distanceUnits maps the unit names accepted by ParseDistance to their length.
+var distanceUnits = map[string]Distance{
+	"mm": Millimeter, "millimeter": Millimeter, "millimeters": Millimeter, "millimetre": Millimeter, "millimetres": Millimeter,
+	"cm": Centimeter, "centimeter": Centimeter, "centimeters": Centimeter, "centimetre": Centimeter, "centimetres": Centimeter,
+	"m": Meter, "meter": Meter, "meters": Meter, "metre": Meter, "metres": Meter,
+	"km": Kilometer, "kilometer": Kilometer, "kilometers": Kilometer, "kilometre": Kilometer, "kilometres": Kilometer,
+	"ft": Foot, "foot": Foot, "feet": Foot,
+	"mi": Mile, "mile": Mile, "miles": Mile,
+	"nmi": NauticalMile, "nautical mile": NauticalMile, "nautical miles": NauticalMile,
+}
+
+// ParseDistance parses a distance made of a decimal number followed by a
+// unit, optionally separated by spaces, such as "12.5km", "3 mi" or "-40ft".
+// Units are mm, cm, m, km, ft, mi and nmi, or their names in full, such as
+// "meters" or "nautical miles", in any case. A unit is required unless the
+// number is zero.
+func ParseDistance(s string) (Distance, error) {
+	trimmed := strings.TrimSpace(s)
+	i := 0
+	for i < len(trimmed) {
+		c := trimmed[i]
+		isExponent := (c == 'e' || c == 'E') && i+1 < len(trimmed) && strings.IndexByte("+-0123456789", trimmed[i+1]) >= 0
+		if !isExponent && strings.IndexByte("+-.0123456789", c) < 0 {
+			break
+		}
+		i++
+	}
+	value, err := strconv.ParseFloat(trimmed[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDistance, s)
+	}
+	unitName := strings.ToLower(strings.Join(strings.Fields(trimmed[i:]), " "))
+	if unitName == "" && value == 0 {
+		return 0, nil
+	}
+	unit, ok := distanceUnits[unitName]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown unit in %q", ErrInvalidDistance, s)
+	}
+	return Distance(value) * unit, nil
+}
+
+// MarshalJSON encodes the distance as a JSON number of meters, which any JSON
+// consumer can read without knowing about units.
+func (d Distance) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(d))
+}
+
+// UnmarshalJSON decodes a JSON number of meters, as encoded by MarshalJSON,
+// or a JSON string in a format accepted by ParseDistance, such as "12.5km".
+// Like the standard types, it leaves d unchanged for a JSON null.
+func (d *Distance) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		parsed, err := ParseDistance(s)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	}
+	var meters float64
+	if err := json.Unmarshal(b, &meters); err != nil {
+		return err
+	}
+	*d = Distance(meters)
+	return nil
+}
@@ -0,0 +1,104 @@
+package gobag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance_Conversions(t *testing.T) {
+	d := 12.5 * Kilometer
+	require.Equal(t, 12500.0, d.Meters())
+	require.Equal(t, 12.5, d.Kilometers())
+	require.InDelta(t, 7.76714, d.Miles(), 1e-5)
+	require.InDelta(t, 6.74946, d.NauticalMiles(), 1e-5)
+	require.InDelta(t, 41010.5, d.Feet(), 0.1)
+
+	require.InDelta(t, 5280, Mile.Feet(), 1e-9)
+	require.Equal(t, 1.0, NauticalMile.NauticalMiles())
+	require.Equal(t, Distance(100), 100*Meter)
+	require.InDelta(t, 3, (0.9144 * Meter).Feet(), 1e-12)
+}
+
+func TestDistance_String(t *testing.T) {
+	testCases := []struct {
+		distance Distance
+		expected string
+	}{
+		{0, "0m"},
+		{350 * Millimeter, "350mm"},
+		{-2 * Centimeter, "-20mm"},
+		{1 * Meter, "1m"},
+		{999.5 * Meter, "999.5m"},
+		{1000 * Meter, "1km"},
+		{42195 * Meter, "42.195km"},
+		{-3 * Kilometer, "-3km"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.distance.String())
+			parsed, err := ParseDistance(tc.expected)
+			require.NoError(t, err)
+			require.InDelta(t, tc.distance.Meters(), parsed.Meters(), 1e-12)
+		})
+	}
+
+	berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+	paris := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+	require.Equal(t, berlin.GreatCircleDistance(&paris), berlin.DistanceTo(&paris).Meters())
+	require.Contains(t, berlin.DistanceTo(&paris).String(), "km")
+}
+
+func TestParseDistance(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Distance
+	}{
+		{"12.5km", 12.5 * Kilometer},
+		{"3 mi", 3 * Mile},
+		{"  -40ft ", -40 * Foot},
+		{"1e3m", Kilometer},
+		{"2.5E-1 KM", 250 * Meter},
+		{"10 Nautical  Miles", 10 * NauticalMile},
+		{"7nmi", 7 * NauticalMile},
+		{"15 cm", 15 * Centimeter},
+		{"1 metre", Meter},
+		{"+6 feet", 6 * Foot},
+		{"0", 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			d, err := ParseDistance(tc.input)
+			require.NoError(t, err)
+			require.InDelta(t, tc.expected.Meters(), d.Meters(), 1e-9)
+		})
+	}
+
+	for _, input := range []string{"", "km", "12", "12 parsecs", "1.2.3m", "NaN m", "Inf km", "12 m m"} {
+		_, err := ParseDistance(input)
+		require.ErrorIs(t, err, ErrInvalidDistance, "input %q", input)
+	}
+}
+
+func TestDistance_JSON(t *testing.T) {
+	type route struct {
+		Length Distance `json:"length"`
+	}
+	b, err := json.Marshal(route{Length: 12.5 * Kilometer})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"length": 12500}`, string(b))
+
+	var decoded route
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, 12.5*Kilometer, decoded.Length)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"length": "3 mi"}`), &decoded))
+	require.Equal(t, 3*Mile, decoded.Length)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"length": null}`), &decoded))
+	require.Equal(t, 3*Mile, decoded.Length)
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"length": "3 furlongs"}`), &decoded), ErrInvalidDistance)
+	require.Error(t, json.Unmarshal([]byte(`{"length": true}`), &decoded))
+}
@@ -0,0 +1,203 @@
+package gobag
+
+import (
+	"math"
+	"slices"
+)
+
+// BoundingBox is an area enclosed by two parallels and two meridians, with
+// coordinates in degrees. A box whose West longitude is greater than its East
+// longitude crosses the antimeridian: it spans from West to 180 and from -180
+// to East. A box spanning every longitude has a West of -180 and an East of
+// 180.
+type BoundingBox struct {
+	South, West, North, East float64
+}
+
+// BoundingBoxOf returns the smallest box containing every point, crossing the
+// antimeridian when that makes it narrower. It returns false if points is
+// empty.
+//
+// Example:
+//
+//	box, _ := BoundingBoxOf([]GeoPoint{
+//		{Latitude: -18.1416, Longitude: 178.4419},  // Suva, Fiji
+//		{Latitude: -13.8333, Longitude: -171.7667}, // Apia, Samoa
+//	})
+//	// box spans 9.8 degrees of longitude, from 178.4419 to -171.7667
+func BoundingBoxOf(points []GeoPoint) (BoundingBox, bool) {
+	if len(points) == 0 {
+		return BoundingBox{}, false
+	}
+	box := BoundingBox{South: math.Inf(1), North: math.Inf(-1)}
+	longitudes := make([]float64, len(points))
+	for i, p := range points {
+		box.South = min(box.South, p.Latitude)
+		box.North = max(box.North, p.Latitude)
+		longitudes[i] = normalizeLongitude(p.Longitude)
+	}
+	// The box leaves out the largest gap between consecutive longitudes,
+	// which is the one across the antimeridian unless another is larger.
+	slices.Sort(longitudes)
+	n := len(longitudes)
+	gapEnd, largest := 0, longitudes[0]+360-longitudes[n-1]
+	for i := 1; i < n; i++ {
+		if gap := longitudes[i] - longitudes[i-1]; gap > largest {
+			gapEnd, largest = i, gap
+		}
+	}
+	box.West, box.East = longitudes[gapEnd], longitudes[(gapEnd+n-1)%n]
+	return box, true
+}
+
+// BoundingBoxAround returns the smallest box enclosing every point within the
+// given distance of center, on the spherical Earth of GreatCircleDistance. The
+// box widens toward the poles, where meridians converge, and a circle reaching
+// a pole encloses every longitude. BoundingBoxAround panics if radius is
+// negative.
+//
+// Example:
+//
+//	center := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+//	box := BoundingBoxAround(&center, 5*Kilometer)
+//	rows, err := db.Query("SELECT name FROM places WHERE lat BETWEEN ? AND ? AND lon BETWEEN ? AND ?",
+//		box.South, box.North, box.West, box.East)
+func BoundingBoxAround(center *GeoPoint, radius Distance) BoundingBox {
+	longitude := normalizeLongitude(center.Longitude)
+	box := BoundingBox{South: center.Latitude, West: longitude, North: center.Latitude, East: longitude}
+	return box.Expand(radius)
+}
+
+// FilterWithinRadius returns the points whose great circle distance to center
+// is at most radius, in their order in points. Points outside the bounding box
+// of the circle are discarded before computing their distance, which makes
+// filtering a large collection around a small radius cheap.
+//
+// Example:
+//
+//	nearby := FilterWithinRadius(stores, &customer, 10*Kilometer)
+func FilterWithinRadius(points []GeoPoint, center *GeoPoint, radius Distance) []GeoPoint {
+	box := BoundingBoxAround(center, radius)
+	var within []GeoPoint
+	for i := range points {
+		if box.Contains(&points[i]) && center.GreatCircleDistance(&points[i]) <= radius.Meters() {
+			within = append(within, points[i])
+		}
+	}
+	return within
+}
+
+// Contains reports whether p lies in b, boundaries included.
+func (b BoundingBox) Contains(p *GeoPoint) bool {
+	return b.South <= p.Latitude && p.Latitude <= b.North && b.containsLongitude(normalizeLongitude(p.Longitude))
+}
+
+// Intersects reports whether b and other share at least one point.
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	if b.South > other.North || other.South > b.North {
+		return false
+	}
+	// Two ranges of longitudes overlap if one of them holds the start of the
+	// other.
+	return b.containsLongitude(other.West) || other.containsLongitude(b.West)
+}
+
+// Union returns the smallest box containing both b and other.
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	union := BoundingBox{South: min(b.South, other.South), West: -180, North: max(b.North, other.North), East: 180}
+	// The narrowest range of longitudes covering both boxes starts where one
+	// of them starts and ends where one of them ends.
+	candidates := []BoundingBox{b, other, {West: b.West, East: other.East}, {West: other.West, East: b.East}}
+	for _, c := range candidates {
+		if c.width() < union.width() && c.coversLongitudes(b) && c.coversLongitudes(other) {
+			union.West, union.East = c.West, c.East
+		}
+	}
+	return union
+}
+
+// Expand returns b grown by margin on every side, the smallest box containing
+// every point within margin of b on the spherical Earth of
+// GreatCircleDistance. A box growing past a pole encloses every longitude.
+// Expand panics if margin is negative.
+func (b BoundingBox) Expand(margin Distance) BoundingBox {
+	if margin < 0 {
+		panic("gobag: negative bounding box margin")
+	}
+	delta := margin.Meters() / earthRadius
+	expanded := BoundingBox{South: b.South - delta/degree, West: -180, North: b.North + delta/degree, East: 180}
+	if expanded.South <= -90 || expanded.North >= 90 {
+		expanded.South, expanded.North = max(expanded.South, -90), min(expanded.North, 90)
+		return expanded
+	}
+	// The meridians bounding a circle around a point of latitude lat are
+	// tangent to it, asin(sin(delta)/cos(lat)) away from the point. They are
+	// the farthest at the latitude of b closest to a pole.
+	lat := max(math.Abs(b.South), math.Abs(b.North)) * degree
+	deltaLon := math.Asin(math.Sin(delta)/math.Cos(lat)) / degree
+	if b.width()+2*deltaLon >= 360 {
+		return expanded
+	}
+	expanded.West = normalizeLongitude(b.West - deltaLon)
+	expanded.East = normalizeLongitude(b.East + deltaLon)
+	return expanded
+}
+
+// Center returns the point of b halfway between its parallels and halfway
+// between its meridians.
+func (b BoundingBox) Center() *GeoPoint {
+	return NewGeoPoint((b.South+b.North)/2, normalizeLongitude(b.West+b.width()/2))
+}
+
+// width returns the number of degrees of longitude spanned by b.
+func (b BoundingBox) width() float64 {
+	if b.West <= b.East {
+		return b.East - b.West
+	}
+	return b.East - b.West + 360
+}
+
+// containsLongitude reports whether the meridian of longitude crosses b.
+func (b BoundingBox) containsLongitude(longitude float64) bool {
+	if b.West <= b.East {
+		return b.West <= longitude && longitude <= b.East
+	}
+	return longitude >= b.West || longitude <= b.East
+}
+
+// coversLongitudes reports whether b spans every longitude spanned by other.
+func (b BoundingBox) coversLongitudes(other BoundingBox) bool {
+	offset := math.Mod(other.West-b.West+360, 360)
+	return offset+other.width() <= b.width()
+}
+
+// distanceTo returns the great circle distance in meters from p to the
+// closest point of b, which is zero if b contains p.
+func (b BoundingBox) distanceTo(p *GeoPoint) float64 {
+	if b.Contains(p) {
+		return 0
+	}
+	angle := math.Inf(1)
+	for _, corner := range []GeoPoint{{b.South, b.West}, {b.South, b.East}, {b.North, b.West}, {b.North, b.East}} {
+		angle = min(angle, p.centralAngle(&corner))
+	}
+	// Along a parallel, the closest point lies on the meridian of p.
+	if b.containsLongitude(normalizeLongitude(p.Longitude)) {
+		for _, latitude := range []float64{b.South, b.North} {
+			angle = min(angle, p.centralAngle(&GeoPoint{Latitude: latitude, Longitude: p.Longitude}))
+		}
+	}
+	// Along a meridian, the closest point is the foot of the perpendicular
+	// from p, if it faces p, or else a corner.
+	lat := p.Latitude * degree
+	for _, longitude := range []float64{b.West, b.East} {
+		deltaLon := (p.Longitude - longitude) * degree
+		if math.Cos(deltaLon) <= 0 {
+			continue
+		}
+		foot := math.Atan2(math.Sin(lat), math.Cos(lat)*math.Cos(deltaLon)) / degree
+		foot = max(b.South, min(b.North, foot))
+		angle = min(angle, p.centralAngle(&GeoPoint{Latitude: foot, Longitude: longitude}))
+	}
+	return angle * earthRadius
+}
@@ -0,0 +1,208 @@
+package gobag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundingBoxOf(t *testing.T) {
+	_, ok := BoundingBoxOf(nil)
+	require.False(t, ok)
+
+	box, ok := BoundingBoxOf([]GeoPoint{{Latitude: 52.52, Longitude: 13.405}})
+	require.True(t, ok)
+	require.Equal(t, BoundingBox{South: 52.52, West: 13.405, North: 52.52, East: 13.405}, box)
+
+	box, ok = BoundingBoxOf([]GeoPoint{
+		{Latitude: 52.52, Longitude: 13.405},
+		{Latitude: 48.8566, Longitude: 2.3522},
+		{Latitude: 40.4168, Longitude: -3.7038},
+	})
+	require.True(t, ok)
+	require.Equal(t, BoundingBox{South: 40.4168, West: -3.7038, North: 52.52, East: 13.405}, box)
+
+	// Fiji and Samoa are closer across the antimeridian.
+	box, ok = BoundingBoxOf([]GeoPoint{
+		{Latitude: -18.1416, Longitude: 178.4419},
+		{Latitude: -13.8333, Longitude: -171.7667},
+		{Latitude: -21.1789, Longitude: 184.8},
+	})
+	require.True(t, ok)
+	require.Equal(t, -21.1789, box.South)
+	require.Equal(t, -13.8333, box.North)
+	require.Equal(t, 178.4419, box.West)
+	require.InDelta(t, -171.7667, box.East, 1e-12)
+}
+
+func TestBoundingBox_Contains(t *testing.T) {
+	europe := BoundingBox{South: 35, West: -10, North: 60, East: 30}
+	require.True(t, europe.Contains(&GeoPoint{Latitude: 48.8566, Longitude: 2.3522}))
+	require.True(t, europe.Contains(&GeoPoint{Latitude: 35, Longitude: 30}))
+	require.False(t, europe.Contains(&GeoPoint{Latitude: 34.9, Longitude: 0}))
+	require.False(t, europe.Contains(&GeoPoint{Latitude: 40, Longitude: -74}))
+	require.True(t, europe.Contains(&GeoPoint{Latitude: 40, Longitude: 370}))
+
+	pacific := BoundingBox{South: -30, West: 170, North: 0, East: -170}
+	require.True(t, pacific.Contains(&GeoPoint{Latitude: -18, Longitude: 178}))
+	require.True(t, pacific.Contains(&GeoPoint{Latitude: -18, Longitude: -175}))
+	require.True(t, pacific.Contains(&GeoPoint{Latitude: -18, Longitude: 180}))
+	require.False(t, pacific.Contains(&GeoPoint{Latitude: -18, Longitude: 0}))
+	require.False(t, pacific.Contains(&GeoPoint{Latitude: -18, Longitude: -160}))
+}
+
+func TestBoundingBox_Intersects(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     BoundingBox
+		expected bool
+	}{
+		{"overlapping", BoundingBox{0, 0, 10, 10}, BoundingBox{5, 5, 15, 15}, true},
+		{"nested", BoundingBox{0, 0, 10, 10}, BoundingBox{2, 2, 3, 3}, true},
+		{"touching", BoundingBox{0, 0, 10, 10}, BoundingBox{10, 10, 20, 20}, true},
+		{"apart in latitude", BoundingBox{0, 0, 10, 10}, BoundingBox{11, 0, 20, 10}, false},
+		{"apart in longitude", BoundingBox{0, 0, 10, 10}, BoundingBox{0, 11, 10, 20}, false},
+		{"across the antimeridian", BoundingBox{-10, 170, 10, -170}, BoundingBox{0, -175, 5, -160}, true},
+		{"both across the antimeridian", BoundingBox{-10, 170, 10, -170}, BoundingBox{0, 175, 5, -175}, true},
+		{"beside the antimeridian", BoundingBox{-10, 170, 10, -170}, BoundingBox{0, -160, 5, 160}, false},
+		{"opposite the antimeridian", BoundingBox{-10, 170, 10, -170}, BoundingBox{-10, -10, 10, 10}, false},
+		{"every longitude", BoundingBox{80, -180, 90, 180}, BoundingBox{85, 45, 86, 46}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.a.Intersects(tc.b))
+			require.Equal(t, tc.expected, tc.b.Intersects(tc.a))
+		})
+	}
+}
+
+func TestBoundingBox_Union(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     BoundingBox
+		expected BoundingBox
+	}{
+		{"disjoint", BoundingBox{0, 0, 10, 10}, BoundingBox{20, 30, 25, 40}, BoundingBox{0, 0, 25, 40}},
+		{"nested", BoundingBox{0, 0, 10, 10}, BoundingBox{2, 2, 3, 3}, BoundingBox{0, 0, 10, 10}},
+		{"across the antimeridian", BoundingBox{0, 160, 10, 170}, BoundingBox{0, -170, 10, -160}, BoundingBox{0, 160, 10, -160}},
+		{"into a crossing box", BoundingBox{0, 170, 10, -170}, BoundingBox{0, -165, 10, -160}, BoundingBox{0, 170, 10, -160}},
+		{"around the world", BoundingBox{0, 0, 10, 170}, BoundingBox{0, 175, 10, 5}, BoundingBox{0, 175, 10, 170}},
+		{"covering the world", BoundingBox{0, 0, 10, 170}, BoundingBox{0, 165, 10, 5}, BoundingBox{0, -180, 10, 180}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.a.Union(tc.b))
+			require.Equal(t, tc.expected, tc.b.Union(tc.a))
+		})
+	}
+}
+
+func TestBoundingBox_Center(t *testing.T) {
+	require.Equal(t, GeoPoint{Latitude: 5, Longitude: 15}, *BoundingBox{0, 10, 10, 20}.Center())
+	require.Equal(t, GeoPoint{Latitude: -5, Longitude: 180}, *BoundingBox{-10, 170, 0, -170}.Center())
+	require.Equal(t, GeoPoint{Latitude: 0, Longitude: -175}, *BoundingBox{-10, 170, 10, -160}.Center())
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	testCases := []struct {
+		name   string
+		center GeoPoint
+		radius Distance
+	}{
+		{"equator", GeoPoint{}, 100 * Kilometer},
+		{"city", GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, 5 * Kilometer},
+		{"antimeridian", GeoPoint{Latitude: -16.5, Longitude: 179.9}, 50 * Kilometer},
+		{"high latitude", GeoPoint{Latitude: -78, Longitude: -100}, 500 * Kilometer},
+		{"pole", GeoPoint{Latitude: 89.5, Longitude: 30}, 100 * Kilometer},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			box := BoundingBoxAround(&tc.center, tc.radius)
+			require.True(t, box.Contains(&tc.center))
+
+			// The box contains every point of the circle, and its bounds are
+			// reached by the circle unless they are the bounds of the world.
+			r := newTestRand()
+			reached := BoundingBox{South: 90, North: -90}
+			for range 5000 {
+				p := tc.center.Destination(r.Float64()*360, tc.radius)
+				require.True(t, box.Expand(Millimeter).Contains(p), "point %v in %v", *p, box)
+				reached.South, reached.North = min(reached.South, p.Latitude), max(reached.North, p.Latitude)
+			}
+			if box.South > -90 {
+				require.InDelta(t, box.South, reached.South, 1e-3)
+			}
+			if box.North < 90 {
+				require.InDelta(t, box.North, reached.North, 1e-3)
+			}
+			if box.West != -180 || box.East != 180 {
+				west := tc.center.Destination(270, tc.radius)
+				east := tc.center.Destination(90, tc.radius)
+				require.Less(t, box.Expand(Millimeter).width()-box.width(), 1e-6)
+				require.True(t, box.containsLongitude(normalizeLongitude(west.Longitude)))
+				require.True(t, box.containsLongitude(normalizeLongitude(east.Longitude)))
+			}
+		})
+	}
+
+	box := BoundingBoxAround(&GeoPoint{Latitude: 0, Longitude: 179.9}, 50*Kilometer)
+	require.Greater(t, box.West, box.East, "box %v crosses the antimeridian", box)
+	// A circle around the North Pole spans every longitude.
+	box = BoundingBoxAround(&GeoPoint{Latitude: 89.9, Longitude: 0}, 50*Kilometer)
+	require.Equal(t, BoundingBox{South: box.South, West: -180, North: 90, East: 180}, box)
+	require.Panics(t, func() { BoundingBoxAround(&GeoPoint{}, -Meter) })
+}
+
+func TestBoundingBox_Expand(t *testing.T) {
+	box := BoundingBox{South: 10, West: 20, North: 30, East: 40}
+	require.Equal(t, box, box.Expand(0))
+
+	expanded := box.Expand(100 * Kilometer)
+	require.InDelta(t, 10-100000/float64(earthRadius)/degree, expanded.South, 1e-12)
+	require.InDelta(t, 30+100000/float64(earthRadius)/degree, expanded.North, 1e-12)
+	// Points within the margin of the box's corners lie in the expanded box.
+	r := newTestRand()
+	for _, corner := range []GeoPoint{{10, 20}, {10, 40}, {30, 20}, {30, 40}} {
+		for range 1000 {
+			p := corner.Destination(r.Float64()*360, 100*Kilometer)
+			require.True(t, expanded.Expand(Millimeter).Contains(p), "point %v in %v", *p, expanded)
+		}
+	}
+
+	// A box growing past a pole or past 360 degrees of longitude encloses
+	// every longitude.
+	expanded = BoundingBox{-80, 0, 0, 10}.Expand(2000 * Kilometer)
+	require.Equal(t, BoundingBox{South: -90, West: -180, North: expanded.North, East: 180}, expanded)
+	expanded = BoundingBox{0, -170, 0, 170}.Expand(1500 * Kilometer)
+	require.Equal(t, -180.0, expanded.West)
+	require.Equal(t, 180.0, expanded.East)
+	require.Panics(t, func() { box.Expand(-Meter) })
+}
+
+func TestFilterWithinRadius(t *testing.T) {
+	r := newTestRand()
+	points := make([]GeoPoint, 5000)
+	for i := range points {
+		points[i] = GeoPoint{Latitude: r.Float64()*180 - 90, Longitude: r.Float64()*360 - 180}
+	}
+	centers := []GeoPoint{
+		{Latitude: 48.8566, Longitude: 2.3522},
+		{Latitude: -16.5, Longitude: 179.9},
+		{Latitude: 88, Longitude: -45},
+	}
+	for _, center := range centers {
+		for _, radius := range []Distance{0, 500 * Kilometer, 2000 * Kilometer, 20000 * Kilometer} {
+			var expected []GeoPoint
+			for _, p := range points {
+				if center.GreatCircleDistance(&p) <= radius.Meters() {
+					expected = append(expected, p)
+				}
+			}
+			require.Equal(t, expected, FilterWithinRadius(points, &center, radius), "%v within %v", center, radius)
+		}
+	}
+
+	near := []GeoPoint{{Latitude: 48.86, Longitude: 2.35}, {Latitude: 52.52, Longitude: 13.405}}
+	require.Equal(t, near[:1], FilterWithinRadius(near, &GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, 10*Kilometer))
+	require.Empty(t, FilterWithinRadius(nil, &GeoPoint{}, Kilometer))
+}
@@ -4,6 +4,12 @@ import (
 	"math"
 )
 
+// earthRadius is the radius of the spherical Earth model used by
+// GreatCircleDistance and the navigation functions of GeoPoint, in meters. The
+// value 6378100 represents an approximate average radius of the Earth and is
+// commonly used for calculations involving distances on Earth.
+const earthRadius = 6378100
+
 // NewGeoPoint creates a new GeoPoint with the given latitude and longitude
 // values. It returns a pointer to the created GeoPoint object.
 func NewGeoPoint(latitude, longitude float64) *GeoPoint {
@@ -33,7 +39,8 @@ type GeoPoint struct {
 // The returned distance represents the actual path that would be traveled on the
 // Earth's surface between the two GeoPoints, assuming a perfect sphere. It provides
 // a more accurate measure of distance than a straight-line distance, especially for
-// longer distances.
+// longer distances. DistanceTo returns the same distance as a Distance, which
+// keeps track of its unit.
 //
 // Example:
 //
@@ -42,6 +49,18 @@ type GeoPoint struct {
 //	distance := point1.GreatCircleDistance(&point2)
 //	fmt.Printf("The great circle distance between Berlin and Paris is: %.2f meters\n", distance)
 func (g *GeoPoint) GreatCircleDistance(gp *GeoPoint) float64 {
+	// The great circle distance between the two GeoPoints is determined by
+	// multiplying the central angle (expressed in radians) by the Earth's
+	// radius (see earthRadius). The resulting value is returned as a float64
+	// representing the distance in meters.
+	return float64(earthRadius) * g.centralAngle(gp)
+}
+
+// centralAngle returns the angle, in radians, between g and gp seen from the
+// center of the Earth, computed with the haversine formula. It is the single
+// implementation of the formula behind GreatCircleDistance and the navigation
+// functions of GeoPoint.
+func (g *GeoPoint) centralAngle(gp *GeoPoint) float64 {
 	// The calculation of the difference in latitude is necessary to determine
 	// the angular separation between the two GeoPoints along the north-south
 	// direction. By converting the difference from degrees to radians, it
@@ -93,19 +112,7 @@ func (g *GeoPoint) GreatCircleDistance(gp *GeoPoint) float64 {
 	// to the square root of the haversine sum divided by the square root of 1
 	// - haversineSum, the central angle is determined. Multiplying the result
 	// by 2 accounts for the symmetrical nature of the great circle.
-	centralAngle := 2 * math.Atan2(math.Sqrt(haversineSum), math.Sqrt(1-haversineSum))
-
-	// The definition of the Earth's radius in meters is necessary to convert
-	// the central angle into an actual distance. The value 6378100 represents
-	// an approximate average radius of the Earth and is commonly used for
-	// calculations involving distances on Earth.
-	earthRadius := 6378100
-
-	// Finally, the great circle distance between the two GeoPoints is
-	// determined by multiplying the central angle (expressed in radians) by
-	// the Earth's radius. The resulting value is returned as a float64
-	// representing the distance in meters.
-	return float64(earthRadius) * centralAngle
+	return 2 * math.Atan2(math.Sqrt(haversineSum), math.Sqrt(1-haversineSum))
 }
 
 // Distance calculates the straight-line distance between two GeoPoint objects.
@@ -0,0 +1,740 @@
+package gobag
+
+import "math"
+
+// Ellipsoid is an ellipsoid of revolution modeling the shape of the Earth.
+type Ellipsoid struct {
+	// SemiMajorAxis is the equatorial radius, in meters.
+	SemiMajorAxis float64
+	// Flattening is (a-b)/a, where a and b are the equatorial and polar
+	// radii. It must be in [0, 1): zero describes a sphere.
+	Flattening float64
+}
+
+var (
+	// WGS84 is the ellipsoid of the World Geodetic System 1984, used by GPS.
+	WGS84 = Ellipsoid{SemiMajorAxis: 6378137, Flattening: 1 / 298.257223563}
+	// GRS80 is the ellipsoid of the Geodetic Reference System 1980, used by
+	// NAD83 and ETRS89.
+	GRS80 = Ellipsoid{SemiMajorAxis: 6378137, Flattening: 1 / 298.257222100882711}
+)
+
+// Geodesic describes the shortest path between two points on an ellipsoid.
+type Geodesic struct {
+	// Distance is the length of the path.
+	Distance Distance
+	// InitialAzimuth is the direction of the path at its first point, in
+	// degrees clockwise from north, in [-180, 180].
+	InitialAzimuth float64
+	// FinalAzimuth is the direction of the path at its second point, in
+	// degrees clockwise from north, in [-180, 180].
+	FinalAzimuth float64
+}
+
+// EllipsoidDistance returns the shortest path from g to gp on the given
+// ellipsoid, such as WGS84. Unlike GreatCircleDistance and Distance, which
+// assume a spherical Earth and may be off by up to 0.5%, it is accurate to
+// within 15 nanometers.
+//
+// EllipsoidDistance solves the inverse geodesic problem with the algorithm of
+// Karney (2013), "Algorithms for geodesics", which converges for every pair of
+// points, including nearly antipodal ones for which Vincenty's formulae fail.
+// The azimuths of a path between antipodal points are not unique; one of the
+// shortest paths is returned. The results are NaN if a latitude is not in
+// [-90, 90]. EllipsoidDistance panics if the flattening of the ellipsoid is
+// not in [0, 1) or its semi-major axis is not positive.
+//
+// Example:
+//
+//	jfk := GeoPoint{Latitude: 40.6397, Longitude: -73.7789}
+//	sin := GeoPoint{Latitude: 1.3592, Longitude: 103.9894}
+//	path := jfk.EllipsoidDistance(&sin, WGS84)
+//	fmt.Printf("%.0f km, heading %.1f°\n", path.Distance.Kilometers(), path.InitialAzimuth)
+func (g *GeoPoint) EllipsoidDistance(gp *GeoPoint, ellipsoid Ellipsoid) Geodesic {
+	return newGeodesicSolver(ellipsoid).inverse(g.Latitude, g.Longitude, gp.Latitude, gp.Longitude)
+}
+
+// The implementation follows GeographicLib, by Charles F. F. Karney, with
+// series expansions in the third flattening n truncated to the sixth order,
+// which is accurate to the round-off of float64 for the flattening of the
+// Earth.
+const (
+	geodesicOrder = 6
+	nA1           = geodesicOrder
+	nC1           = geodesicOrder
+	nA2           = geodesicOrder
+	nC2           = geodesicOrder
+	nA3           = geodesicOrder
+	nC3           = geodesicOrder
+
+	geodesicMaxit1 = 20
+	geodesicMaxit2 = geodesicMaxit1 + 53 + 10
+
+	degree = math.Pi / 180
+)
+
+var (
+	geodesicTiny    = math.Sqrt(0x1p-1022)
+	geodesicTol0    = 0x1p-52
+	geodesicTol1    = 200 * geodesicTol0
+	geodesicTol2    = math.Sqrt(geodesicTol0)
+	geodesicTolb    = geodesicTol0
+	geodesicXthresh = 1000 * geodesicTol2
+)
+
+// Numerators and denominators of the coefficients of the series of the
+// geodesic integrals, in the layout of GeographicLib: each polynomial lists
+// its coefficients from the highest order down, followed by its denominator.
+var (
+	// (1-eps)*A1-1, polynomial in eps^2 of order 3.
+	a1Coeff = [...]float64{1, 4, 64, 0, 256}
+	// C1[l]/eps^l, polynomials in eps^2, for l from 1 to 6.
+	c1Coeff = [...]float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	// (1+eps)*A2-1, polynomial in eps^2 of order 3.
+	a2Coeff = [...]float64{-11, -28, -192, 0, 256}
+	// C2[l]/eps^l, polynomials in eps^2, for l from 1 to 6.
+	c2Coeff = [...]float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	// Coefficients of A3 in eps^5 down to eps^0, polynomials in n.
+	a3Coeff = [...]float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	// Coefficients of C3[l] in eps^5 down to eps^l, polynomials in n, for l
+	// from 1 to 5.
+	c3Coeff = [...]float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+)
+
+// geodesicSolver holds the constants of an ellipsoid used to solve geodesic
+// problems on it.
+type geodesicSolver struct {
+	a, f, f1, ep2, n, b, etol2 float64
+	a3x                        [nA3]float64
+	c3x                        [nC3 * (nC3 - 1) / 2]float64
+}
+
+func newGeodesicSolver(e Ellipsoid) *geodesicSolver {
+	if !(e.Flattening >= 0 && e.Flattening < 1) || !(e.SemiMajorAxis > 0) {
+		panic("gobag: ellipsoid flattening must be in [0, 1) and its semi-major axis positive")
+	}
+	f := e.Flattening
+	s := &geodesicSolver{
+		a:  e.SemiMajorAxis,
+		f:  f,
+		f1: 1 - f,
+		n:  f / (2 - f),
+		b:  e.SemiMajorAxis * (1 - f),
+	}
+	e2 := f * (2 - f)
+	s.ep2 = e2 / sq(s.f1)
+	s.etol2 = 0.1 * geodesicTol2 / math.Sqrt(max(0.001, f)*min(1, 1-f/2)/2)
+
+	o, k := 0, 0
+	for j := nA3 - 1; j >= 0; j-- {
+		m := min(nA3-j-1, j)
+		s.a3x[k] = polyval(m, a3Coeff[o:], s.n) / a3Coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+	o, k = 0, 0
+	for l := 1; l < nC3; l++ {
+		for j := nC3 - 1; j >= l; j-- {
+			m := min(nC3-j-1, j)
+			s.c3x[k] = polyval(m, c3Coeff[o:], s.n) / c3Coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+	return s
+}
+
+// inverse solves the inverse geodesic problem between two points given in
+// degrees. It first reduces the problem to a canonical form, with
+// 0 <= lon12 <= 180 and -90 <= lat1 <= -|lat2|, then either solves it
+// directly for meridional, equatorial and short paths, or finds the initial
+// azimuth by Newton's method, falling back to bisection.
+func (s *geodesicSolver) inverse(lat1, lon1, lat2, lon2 float64) Geodesic {
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if math.Signbit(lon12) {
+		lonsign = -1
+	}
+	lon12 *= lonsign
+	lon12s *= lonsign
+	lam12 := lon12 * degree
+	slam12, clam12 := sincosde(lon12, lon12s)
+	// The supplementary longitude difference.
+	lon12s = (180 - lon12) - lon12s
+
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := -1.0
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1, cbet1 = norm2(s.f1*sbet1, cbet1)
+	cbet1 = max(geodesicTiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2, cbet2 = norm2(s.f1*sbet2, cbet2)
+	cbet2 = max(geodesicTiny, cbet2)
+
+	// Force bet2 = ±bet1 exactly when the measure of their difference
+	// vanishes, which Lambda12 relies on.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + s.ep2*sq(sbet1))
+	dn2 := math.Sqrt(1 + s.ep2*sq(sbet2))
+
+	var s12x, salp1, calp1, salp2, calp2 float64
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// The geodesic may run along the meridian: head to the target
+		// longitude, arriving northward.
+		salp1, calp1 = slam12, clam12
+		salp2, calp2 = 0, 1
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+		sig12 := math.Atan2(max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+		s12b, m12b := s.lengths(s.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+		if sig12 < 1 || m12b >= 0 {
+			if sig12 < 3*geodesicTiny || sig12 < geodesicTol0 && (s12b < 0 || m12b < 0) {
+				s12b = 0
+			}
+			s12x = s12b * s.b
+		} else {
+			meridian = false
+		}
+	}
+
+	switch {
+	case meridian:
+	case sbet1 == 0 && (s.f <= 0 || lon12s >= s.f*180):
+		// The geodesic runs along the equator.
+		salp1, calp1 = 1, 0
+		salp2, calp2 = 1, 0
+		s12x = s.a * lam12
+	default:
+		var sig12, dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = s.inverseStart(sbet1, cbet1, sbet2, cbet2, lam12, slam12, clam12)
+		if sig12 >= 0 {
+			// Short lines are solved by inverseStart.
+			s12x = sig12 * s.b * dnm
+			break
+		}
+
+		// Solve lambda12(alp1) = lam12 by Newton's method, keeping a
+		// bracket of the root to bisect whenever a Newton step would leave
+		// it or the derivative is not positive.
+		var r lambdaResult
+		salp1a, calp1a, salp1b, calp1b := geodesicTiny, 1.0, geodesicTiny, -1.0
+		tripn, tripb := false, false
+		for numit := 0; ; numit++ {
+			r = s.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < geodesicMaxit1)
+			v := r.lam12
+			tolerance := geodesicTol0
+			if tripn {
+				tolerance *= 8
+			}
+			if tripb || !(math.Abs(v) >= tolerance) || numit == geodesicMaxit2 {
+				break
+			}
+			if v > 0 && (numit > geodesicMaxit1 || calp1/salp1 > calp1b/salp1b) {
+				salp1b, calp1b = salp1, calp1
+			} else if v < 0 && (numit > geodesicMaxit1 || calp1/salp1 < calp1a/salp1a) {
+				salp1a, calp1a = salp1, calp1
+			}
+			if numit < geodesicMaxit1 && r.dlam12 > 0 {
+				if dalp1 := -v / r.dlam12; math.Abs(dalp1) < math.Pi {
+					sdalp1, cdalp1 := math.Sincos(dalp1)
+					if nsalp1 := salp1*cdalp1 + calp1*sdalp1; nsalp1 > 0 {
+						salp1, calp1 = norm2(nsalp1, calp1*cdalp1-salp1*sdalp1)
+						// Convergence may not be quadratic where the slope
+						// vanishes: test against epsilon, not its root.
+						tripn = math.Abs(v) <= 16*geodesicTol0
+						continue
+					}
+				}
+			}
+			salp1, calp1 = norm2((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+			tripn = false
+			tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodesicTolb ||
+				math.Abs(salp1-salp1b)+(calp1-calp1b) < geodesicTolb
+		}
+		salp2, calp2 = r.salp2, r.calp2
+		s12b, _ := s.lengths(r.eps, r.sig12, r.ssig1, r.csig1, dn1, r.ssig2, r.csig2, dn2)
+		s12x = s12b * s.b
+	}
+
+	// Undo the reduction to the canonical form.
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+	return Geodesic{
+		Distance:       Distance(0 + s12x),
+		InitialAzimuth: atan2d(salp1, calp1),
+		FinalAzimuth:   atan2d(salp2, calp2),
+	}
+}
+
+// inverseStart returns a starting point for Newton's method in salp1 and
+// calp1, with sig12 = -1. For short lines, which need no iteration, it solves
+// the problem and returns sig12 >= 0 along with salp2, calp2 and dnm.
+func (s *geodesicSolver) inverseStart(sbet1, cbet1, sbet2, cbet2, lam12, slam12, clam12 float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	// bet12 = bet2 - bet1 in [0, pi); bet12a = bet2 + bet1 in (-pi, 0]
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := sq(sbet1 + sbet2)
+		sbetm2 /= sbetm2 + sq(cbet1+cbet2)
+		dnm = math.Sqrt(1 + s.ep2*sbetm2)
+		somg12, comg12 = math.Sincos(lam12 / (s.f1 * dnm))
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*sq(somg12)/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < s.etol2:
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*sq(somg12)/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(s.n) > 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(s.n)*math.Pi*sq(cbet1):
+		// The spherical approximation is good enough.
+	default:
+		// Nearly antipodal points: scale to coordinates where the antipodal
+		// point is at the origin and the singular point at (-1, 0).
+		lam12x := math.Atan2(-slam12, -clam12)
+		k2 := sq(sbet1) * s.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := s.f * cbet1 * s.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+		if y > -geodesicTol1 && x > -1-geodesicXthresh {
+			salp1 = min(1, -x)
+			calp1 = -math.Sqrt(1 - sq(salp1))
+		} else {
+			// Estimate omg12 by solving the astroid problem, then use the
+			// spherical formula to estimate alp1.
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+		}
+	}
+	// The backwards check lets NaN through.
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+type lambdaResult struct {
+	// lam12 is the difference between the longitude reached with the
+	// initial azimuth and the target longitude, and dlam12 its derivative.
+	lam12, dlam12                          float64
+	salp2, calp2                           float64
+	sig12, ssig1, csig1, ssig2, csig2, eps float64
+}
+
+// lambda12 follows the geodesic leaving the first point with the azimuth
+// (salp1, calp1) to the latitude of the second point, and returns how far its
+// longitude overshoots the target longitude (slam120, clam120).
+func (s *geodesicSolver) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool) lambdaResult {
+	if sbet1 == 0 && calp1 == 0 {
+		// Break the degeneracy of the equatorial line.
+		calp1 = -geodesicTiny
+	}
+	var r lambdaResult
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	// tan(bet1) = tan(sig1) * cos(alp1); tan(omg1) = sin(alp0) * tan(sig1)
+	somg1 := salp0 * sbet1
+	comg1 := calp1 * cbet1
+	r.ssig1, r.csig1 = norm2(sbet1, comg1)
+
+	// Enforce symmetries when |bet2| = -bet1.
+	if cbet2 != cbet1 {
+		r.salp2 = salp0 / cbet2
+	} else {
+		r.salp2 = salp1
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var d float64
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			d = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		r.calp2 = math.Sqrt(sq(calp1*cbet1)+d) / cbet2
+	} else {
+		r.calp2 = math.Abs(calp1)
+	}
+	somg2 := salp0 * sbet2
+	comg2 := r.calp2 * cbet2
+	r.ssig2, r.csig2 = norm2(sbet2, comg2)
+
+	// sig12 = sig2 - sig1 and omg12 = omg2 - omg1, limited to [0, pi].
+	r.sig12 = math.Atan2(max(0, r.csig1*r.ssig2-r.ssig1*r.csig2)+0, r.csig1*r.csig2+r.ssig1*r.ssig2)
+	somg12 := max(0, comg1*somg2-somg1*comg2) + 0
+	comg12 := comg1*comg2 + somg1*somg2
+	// eta = omg12 - lam120
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := sq(calp0) * s.ep2
+	r.eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	var c3 [nC3]float64
+	s.c3f(r.eps, c3[:])
+	b312 := sinSeries(r.ssig2, r.csig2, c3[:]) - sinSeries(r.ssig1, r.csig1, c3[:])
+	r.lam12 = eta - s.f*s.a3f(r.eps)*salp0*(r.sig12+b312)
+
+	if diffp {
+		if r.calp2 == 0 {
+			r.dlam12 = -2 * s.f1 * dn1 / sbet1
+		} else {
+			_, m12b := s.lengths(r.eps, r.sig12, r.ssig1, r.csig1, dn1, r.ssig2, r.csig2, dn2)
+			r.dlam12 = m12b * s.f1 / (r.calp2 * cbet2)
+		}
+	}
+	return r
+}
+
+// lengths returns the distance s12b and the reduced length m12b of a
+// geodesic segment, both divided by the semi-minor axis.
+func (s *geodesicSolver) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64) (s12b, m12b float64) {
+	var c1, c2 [nC1 + 1]float64
+	a1 := a1m1f(eps)
+	c1f(eps, c1[:])
+	a2 := a2m1f(eps)
+	c2f(eps, c2[:])
+	m0x := a1 - a2
+	a1++
+	a2++
+
+	b1 := sinSeries(ssig2, csig2, c1[:]) - sinSeries(ssig1, csig1, c1[:])
+	s12b = a1 * (sig12 + b1)
+	b2 := sinSeries(ssig2, csig2, c2[:]) - sinSeries(ssig1, csig1, c2[:])
+	j12 := m0x*sig12 + (a1*b1 - a2*b2)
+	// The parentheses ensure accurate cancellation for coincident points.
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	return s12b, m12b
+}
+
+func (s *geodesicSolver) a3f(eps float64) float64 {
+	return polyval(nA3-1, s.a3x[:], eps)
+}
+
+// c3f sets c[1] through c[nC3-1].
+func (s *geodesicSolver) c3f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < nC3; l++ {
+		m := nC3 - l - 1
+		mult *= eps
+		c[l] = mult * polyval(m, s.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+func a1m1f(eps float64) float64 {
+	m := nA1 / 2
+	t := polyval(m, a1Coeff[:], sq(eps)) / a1Coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f sets c[1] through c[nC1].
+func c1f(eps float64, c []float64) {
+	eps2, d := sq(eps), eps
+	o := 0
+	for l := 1; l <= nC1; l++ {
+		m := (nC1 - l) / 2
+		c[l] = d * polyval(m, c1Coeff[o:], eps2) / c1Coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+func a2m1f(eps float64) float64 {
+	m := nA2 / 2
+	t := polyval(m, a2Coeff[:], sq(eps)) / a2Coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f sets c[1] through c[nC2].
+func c2f(eps float64, c []float64) {
+	eps2, d := sq(eps), eps
+	o := 0
+	for l := 1; l <= nC2; l++ {
+		m := (nC2 - l) / 2
+		c[l] = d * polyval(m, c2Coeff[o:], eps2) / c2Coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// sinSeries returns the sum of c[i] * sin(2*i*x) for i from 1 to len(c)-1,
+// using Clenshaw summation. c[0] is unused.
+func sinSeries(sinx, cosx float64, c []float64) float64 {
+	k, n := len(c), len(c)-1
+	ar := 2 * (cosx - sinx) * (cosx + sinx) // 2 * cos(2 * x)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	return 2 * sinx * cosx * y0 // sin(2 * x) * y0
+}
+
+// astroid returns the positive root k of
+// k^4 + 2k^3 - (x^2 + y^2 - 1)k^2 - 2y^2k - y^2 = 0.
+func astroid(x, y float64) float64 {
+	p, q := sq(x), sq(y)
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		// y = 0 with |x| <= 1.
+		return 0
+	}
+	// The equations for s and t are multiplied by r^3 and r, which avoids
+	// dividing by r = 0.
+	S := p * q / 4
+	r2 := sq(r)
+	r3 := r * r2
+	// The discriminant vanishes on the evolute p^(1/3) + q^(1/3) = 1.
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		// Pick the sign of the root maximizing |T3| to avoid cancellation.
+		T3 := S + r3
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc)
+		}
+		T := math.Cbrt(T3)
+		if T != 0 {
+			u += T + r2/T
+		}
+	} else {
+		// T is complex, but u is real.
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(sq(u) + q)
+	// Avoid cancellation when u < 0.
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+sq(w)) + w)
+}
+
+// polyval evaluates the polynomial of degree n whose coefficients, from the
+// highest order down, are p[0] through p[n].
+func polyval(n int, p []float64, x float64) float64 {
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+func sq(x float64) float64 {
+	return x * x
+}
+
+// norm2 scales (x, y) to a unit vector.
+func norm2(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
+
+// twoSum returns the sum of u and v rounded to a float64, and the rounding
+// error.
+func twoSum(u, v float64) (float64, float64) {
+	s := u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	if s == 0 {
+		return s, s
+	}
+	return s, 0 - (up + vpp)
+}
+
+// angDiff returns lon2 - lon1 reduced to [-180, 180], as a rounded value and
+// its rounding error. The result is -180 only for westward differences.
+func angDiff(lon1, lon2 float64) (float64, float64) {
+	d, e := twoSum(math.Remainder(-lon1, 360), math.Remainder(lon2, 360))
+	d, e = twoSum(math.Remainder(d, 360), e)
+	if d == 0 || math.Abs(d) == 180 {
+		if e == 0 {
+			d = math.Copysign(d, lon2-lon1)
+		} else {
+			d = math.Copysign(d, -e)
+		}
+	}
+	return d, e
+}
+
+// angRound rounds tiny angles so that points very close to the equator are
+// treated as on it.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	if w := z - y; w > 0 {
+		y = z - w
+	}
+	return math.Copysign(y, x)
+}
+
+func latFix(lat float64) float64 {
+	if math.Abs(lat) > 90 {
+		return math.NaN()
+	}
+	return lat
+}
+
+// sincosd returns the sine and cosine of x degrees, exact for multiples of
+// 90 degrees.
+func sincosd(x float64) (float64, float64) {
+	r := math.Remainder(x, 90)
+	return sincosdQuadrant(r, int(math.Round((x-r)/90)), x)
+}
+
+// sincosde returns the sine and cosine of x+t degrees, where t is a small
+// correction such as the rounding error of x.
+func sincosde(x, t float64) (float64, float64) {
+	r := math.Remainder(x, 90)
+	return sincosdQuadrant(angRound(r+t), int(math.Round((x-r)/90)), x)
+}
+
+// sincosdQuadrant returns the sine and cosine of r + 90*q degrees, for r in
+// [-45, 45], giving a zero sine the sign of x.
+func sincosdQuadrant(r float64, q int, x float64) (sinx, cosx float64) {
+	s, c := math.Sincos(r * degree)
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	cosx += 0
+	if sinx == 0 {
+		sinx = math.Copysign(sinx, x)
+	}
+	return sinx, cosx
+}
+
+// atan2d returns the angle of (x, y) in degrees, in [-180, 180], reducing the
+// arguments first so that multiples of 45 degrees are exact.
+func atan2d(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if math.Signbit(x) {
+		x = -x
+		q++
+	}
+	ang := math.Atan2(y, x) / degree
+	switch q {
+	case 1:
+		ang = math.Copysign(180, y) - ang
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
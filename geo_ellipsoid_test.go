@@ -0,0 +1,129 @@
+package gobag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoPoint_EllipsoidDistance(t *testing.T) {
+	// Reference solutions on WGS84 computed by GeographicLib: its inverse
+	// example from JFK to LHR, the nearly antipodal example of Karney's
+	// "Algorithms for geodesics", and cases of its test suite.
+	testCases := []struct {
+		name                         string
+		point1, point2               GeoPoint
+		distance, azimuth1, azimuth2 float64
+	}{
+		{
+			name:     "JFK to LHR",
+			point1:   GeoPoint{Latitude: 40.6, Longitude: -73.8},
+			point2:   GeoPoint{Latitude: 51.6, Longitude: -0.5},
+			distance: 5551759.400319, azimuth1: 51.198882845579824, azimuth2: 107.821776735514248,
+		},
+		{
+			name:     "nearly antipodal",
+			point1:   GeoPoint{Latitude: -30, Longitude: 0},
+			point2:   GeoPoint{Latitude: 29.9, Longitude: 179.8},
+			distance: 19989832.827610, azimuth1: 161.890524736, azimuth2: 18.090737246,
+		},
+		{
+			point1:   GeoPoint{Latitude: 35.60777, Longitude: -139.44815},
+			point2:   GeoPoint{Latitude: -11.17491, Longitude: -69.95921},
+			distance: 8935244.5604818305, azimuth1: 111.098748429560326, azimuth2: 129.289270889708762,
+		},
+		{
+			point1:   GeoPoint{Latitude: 55.52454, Longitude: 106.05087},
+			point2:   GeoPoint{Latitude: 77.03196, Longitude: 197.18234},
+			distance: 4105086.1713924406, azimuth1: 22.020059880982801, azimuth2: 109.112041110671519,
+		},
+		{
+			point1:   GeoPoint{Latitude: -17.42761, Longitude: 173.34268},
+			point2:   GeoPoint{Latitude: -15.84784, Longitude: 5.93557},
+			distance: 16076603.1631180673, azimuth1: -159.033557661192928, azimuth2: -20.787484651536988,
+		},
+		{
+			point1:   GeoPoint{Latitude: 6.96833, Longitude: 52.74123},
+			point2:   GeoPoint{Latitude: -7.39675, Longitude: 206.17291},
+			distance: 17102477.2496958388, azimuth1: 92.581585386317712, azimuth2: 90.721692165923907,
+		},
+		{
+			point1:   GeoPoint{Latitude: -87.85331, Longitude: 85.66836},
+			point2:   GeoPoint{Latitude: 66.48646, Longitude: 16.09921},
+			distance: 17286615.3147144645, azimuth1: -65.120313040242748, azimuth2: -4.888658719272296,
+		},
+		{
+			point1:   GeoPoint{Latitude: -25.72959, Longitude: -144.90758},
+			point2:   GeoPoint{Latitude: -57.70581, Longitude: -269.17879},
+			distance: 9413446.7452453107, azimuth1: -153.647468693117198, azimuth2: -48.343983158876487,
+		},
+		{
+			point1:   GeoPoint{Latitude: -19.79938, Longitude: -174.47484},
+			point2:   GeoPoint{Latitude: -11.99349, Longitude: -154.35109},
+			distance: 2319004.8601169389, azimuth1: 71.167275780171533, azimuth2: 65.589099775199228,
+		},
+		{
+			point1:   GeoPoint{Latitude: -29.47124, Longitude: 95.14681},
+			point2:   GeoPoint{Latitude: -27.46601, Longitude: -69.15955},
+			distance: 13487015.8381145492, azimuth1: -163.779130441688382, azimuth2: -15.909335945554969,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := tc.point1.EllipsoidDistance(&tc.point2, WGS84)
+			require.InDelta(t, tc.distance, path.Distance.Meters(), 1e-6)
+			require.InDelta(t, tc.azimuth1, path.InitialAzimuth, 1e-9)
+			require.InDelta(t, tc.azimuth2, path.FinalAzimuth, 1e-9)
+
+			// The reverse path runs backwards.
+			back := tc.point2.EllipsoidDistance(&tc.point1, WGS84)
+			require.InDelta(t, path.Distance.Meters(), back.Distance.Meters(), 1e-6)
+			require.InDelta(t, path.InitialAzimuth, angNormalizeTest(back.FinalAzimuth+180), 1e-9)
+			require.InDelta(t, path.FinalAzimuth, angNormalizeTest(back.InitialAzimuth+180), 1e-9)
+		})
+	}
+
+	t.Run("special cases", func(t *testing.T) {
+		origin := GeoPoint{}
+		require.Equal(t, 0.0, origin.EllipsoidDistance(&origin, WGS84).Distance.Meters())
+
+		// Half the meridian, whether along the equator's antipodes or from
+		// pole to pole.
+		const halfMeridian = 20003931.4586254
+		path := origin.EllipsoidDistance(&GeoPoint{Longitude: 180}, WGS84)
+		require.InDelta(t, halfMeridian, path.Distance.Meters(), 1e-6)
+		path = (&GeoPoint{Latitude: 90}).EllipsoidDistance(&GeoPoint{Latitude: -90}, WGS84)
+		require.InDelta(t, halfMeridian, path.Distance.Meters(), 1e-6)
+		require.Equal(t, 180.0, path.InitialAzimuth)
+
+		// Along the equator.
+		path = origin.EllipsoidDistance(&GeoPoint{Longitude: 90}, WGS84)
+		require.InDelta(t, 6378137*math.Pi/2, path.Distance.Meters(), 1e-6)
+		require.Equal(t, 90.0, path.InitialAzimuth)
+		require.Equal(t, 90.0, path.FinalAzimuth)
+
+		require.True(t, math.IsNaN(origin.EllipsoidDistance(&GeoPoint{Latitude: 91}, WGS84).Distance.Meters()))
+	})
+
+	t.Run("ellipsoids", func(t *testing.T) {
+		berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+		paris := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+
+		// On a sphere of the same radius, geodesics are great circles.
+		sphere := Ellipsoid{SemiMajorAxis: earthRadius}
+		require.InDelta(t, berlin.GreatCircleDistance(&paris), berlin.EllipsoidDistance(&paris, sphere).Distance.Meters(), 1e-6)
+
+		// GRS80 and WGS84 differ by 0.1 mm in their polar radius.
+		require.InDelta(t, berlin.EllipsoidDistance(&paris, WGS84).Distance.Meters(), berlin.EllipsoidDistance(&paris, GRS80).Distance.Meters(), 1e-4)
+
+		require.Panics(t, func() { berlin.EllipsoidDistance(&paris, Ellipsoid{SemiMajorAxis: 6378137, Flattening: -0.1}) })
+		require.Panics(t, func() { berlin.EllipsoidDistance(&paris, Ellipsoid{}) })
+	})
+}
+
+// angNormalizeTest reduces an angle in degrees to [-180, 180).
+func angNormalizeTest(x float64) float64 {
+	return math.Mod(math.Mod(x+180, 360)+360, 360) - 180
+}
@@ -0,0 +1,129 @@
+package gobag
+
+import "math"
+
+// The navigation functions below model the Earth as a sphere of radius
+// earthRadius, like GreatCircleDistance, so that their results are consistent
+// with it: the destination reached by following the initial bearing from g to
+// gp for DistanceTo(gp) is gp. Bearings are in degrees clockwise from north,
+// in [0, 360). For accuracy better than 0.5%, see EllipsoidDistance.
+
+// DistanceTo returns the great circle distance from g to gp, as computed by
+// GreatCircleDistance.
+//
+// Example:
+//
+//	berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+//	paris := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+//	fmt.Println(berlin.DistanceTo(&paris)) // 878.44119...km
+func (g *GeoPoint) DistanceTo(gp *GeoPoint) Distance {
+	return Distance(g.GreatCircleDistance(gp))
+}
+
+// InitialBearing returns the bearing at g of the great circle path from g to
+// gp. It returns 0 if the points coincide.
+//
+// Example:
+//
+//	berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+//	paris := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+//	bearing := berlin.InitialBearing(&paris) // about 246°, west-southwest
+func (g *GeoPoint) InitialBearing(gp *GeoPoint) float64 {
+	lat1, lat2 := g.Latitude*degree, gp.Latitude*degree
+	deltaLon := (gp.Longitude - g.Longitude) * degree
+	y := math.Sin(deltaLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(deltaLon)
+	return normalizeBearing(math.Atan2(y, x) / degree)
+}
+
+// FinalBearing returns the bearing at gp of the great circle path from g to
+// gp. Unlike on a rhumb line, it generally differs from the initial bearing.
+func (g *GeoPoint) FinalBearing(gp *GeoPoint) float64 {
+	return normalizeBearing(gp.InitialBearing(g) + 180)
+}
+
+// Destination returns the point reached by traveling the given distance from g
+// along the great circle leaving g with the given bearing. The longitude of
+// the destination is in [-180, 180].
+//
+// Example:
+//
+//	origin := GeoPoint{Latitude: 51.4778, Longitude: -0.0014}
+//	dest := origin.Destination(90, 10*Kilometer) // 10 km east of Greenwich
+func (g *GeoPoint) Destination(bearing float64, distance Distance) *GeoPoint {
+	delta := distance.Meters() / earthRadius
+	theta := bearing * degree
+	lat1, lon1 := g.Latitude*degree, g.Longitude*degree
+	sinLat2 := math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta)
+	lat2 := math.Asin(max(-1, min(1, sinLat2)))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*sinLat2)
+	return NewGeoPoint(lat2/degree, normalizeLongitude(lon2/degree))
+}
+
+// Midpoint returns the point halfway between g and gp along the great circle
+// path joining them.
+func (g *GeoPoint) Midpoint(gp *GeoPoint) *GeoPoint {
+	return g.Interpolate(gp, 0.5)
+}
+
+// Interpolate returns the point at the given fraction of the great circle
+// path from g to gp: 0 returns g, 1 returns gp and 0.5 their midpoint.
+// Fractions outside [0, 1] extrapolate along the great circle. The path, and
+// therefore the result, is undefined for antipodal points.
+func (g *GeoPoint) Interpolate(gp *GeoPoint, fraction float64) *GeoPoint {
+	delta := g.centralAngle(gp)
+	if delta == 0 {
+		return NewGeoPoint(g.Latitude, g.Longitude)
+	}
+	// Spherical linear interpolation between the unit vectors of the points.
+	a := math.Sin((1-fraction)*delta) / math.Sin(delta)
+	b := math.Sin(fraction*delta) / math.Sin(delta)
+	lat1, lon1 := g.Latitude*degree, g.Longitude*degree
+	lat2, lon2 := gp.Latitude*degree, gp.Longitude*degree
+	x := a*math.Cos(lat1)*math.Cos(lon1) + b*math.Cos(lat2)*math.Cos(lon2)
+	y := a*math.Cos(lat1)*math.Sin(lon1) + b*math.Cos(lat2)*math.Sin(lon2)
+	z := a*math.Sin(lat1) + b*math.Sin(lat2)
+	return NewGeoPoint(math.Atan2(z, math.Hypot(x, y))/degree, math.Atan2(y, x)/degree)
+}
+
+// CrossTrackDistance returns the distance from g to the great circle through
+// start and end, as traveled from start to end. It is positive if g lies to
+// the right of the path and negative if it lies to the left.
+//
+// Example:
+//
+//	deviation := position.CrossTrackDistance(&waypoint1, &waypoint2)
+func (g *GeoPoint) CrossTrackDistance(start, end *GeoPoint) Distance {
+	delta13 := start.centralAngle(g)
+	theta := (start.InitialBearing(g) - start.InitialBearing(end)) * degree
+	return Distance(math.Asin(max(-1, min(1, math.Sin(delta13)*math.Sin(theta)))) * earthRadius)
+}
+
+// AlongTrackDistance returns the distance from start to the point of the great
+// circle through start and end closest to g. It is negative if that point
+// lies behind start, as traveled from start to end.
+func (g *GeoPoint) AlongTrackDistance(start, end *GeoPoint) Distance {
+	delta13 := start.centralAngle(g)
+	theta := (start.InitialBearing(g) - start.InitialBearing(end)) * degree
+	// In the right spherical triangle formed by start, g and its projection,
+	// tan(along) = tan(delta13) * cos(theta).
+	return Distance(math.Atan2(math.Sin(delta13)*math.Cos(theta), math.Cos(delta13)) * earthRadius)
+}
+
+// normalizeBearing reduces a bearing in degrees to [0, 360).
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, 360)
+	if bearing < 0 {
+		bearing += 360
+	}
+	if bearing == 360 {
+		return 0
+	}
+	return bearing + 0
+}
+
+// normalizeLongitude reduces a longitude in degrees to [-180, 180].
+func normalizeLongitude(longitude float64) float64 {
+	longitude = math.Remainder(longitude, 360)
+	return longitude + 0
+}
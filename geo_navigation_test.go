@@ -0,0 +1,107 @@
+package gobag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireGeoPointInDelta checks that two points are within delta degrees of
+// each other.
+func requireGeoPointInDelta(t *testing.T, expected, actual *GeoPoint, delta float64) {
+	t.Helper()
+	require.InDelta(t, expected.Latitude, actual.Latitude, delta, "latitude of %v", *actual)
+	require.InDelta(t, expected.Longitude, actual.Longitude, delta, "longitude of %v", *actual)
+}
+
+func TestGeoPoint_Bearing(t *testing.T) {
+	origin := GeoPoint{}
+	require.InDelta(t, 0, origin.InitialBearing(&GeoPoint{Latitude: 10}), 1e-12)
+	require.InDelta(t, 90, origin.InitialBearing(&GeoPoint{Longitude: 10}), 1e-12)
+	require.InDelta(t, 180, origin.InitialBearing(&GeoPoint{Latitude: -10}), 1e-12)
+	require.InDelta(t, 270, origin.InitialBearing(&GeoPoint{Longitude: -10}), 1e-12)
+	require.Equal(t, 0.0, origin.InitialBearing(&origin))
+
+	// Between two points of the 45th parallel, the great circle arcs north of it.
+	start := GeoPoint{Latitude: 45, Longitude: 0}
+	end := GeoPoint{Latitude: 45, Longitude: 90}
+	initial, final := start.InitialBearing(&end), start.FinalBearing(&end)
+	require.InDelta(t, 54.73561032, initial, 1e-8)
+	require.InDelta(t, 125.26438968, final, 1e-8)
+
+	berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+	paris := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+	require.InDelta(t, 246, berlin.InitialBearing(&paris), 1)
+	require.InDelta(t, normalizeBearing(paris.InitialBearing(&berlin)+180), berlin.FinalBearing(&paris), 1e-9)
+}
+
+func TestGeoPoint_Destination(t *testing.T) {
+	origin := GeoPoint{}
+	quarter := Distance(earthRadius * math.Pi / 2)
+	requireGeoPointInDelta(t, &GeoPoint{Longitude: 90}, origin.Destination(90, quarter), 1e-9)
+	requireGeoPointInDelta(t, &GeoPoint{Latitude: 90}, origin.Destination(0, quarter), 1e-9)
+	requireGeoPointInDelta(t, &GeoPoint{Latitude: -45}, origin.Destination(180, quarter/2), 1e-9)
+	// Crossing the antimeridian wraps the longitude.
+	requireGeoPointInDelta(t, &GeoPoint{Longitude: -170}, (&GeoPoint{Longitude: 170}).Destination(90, quarter*20/90), 1e-9)
+
+	// Following the initial bearing for the great circle distance reaches the
+	// other point.
+	pairs := [][2]GeoPoint{
+		{{Latitude: 52.5200, Longitude: 13.4050}, {Latitude: 48.8566, Longitude: 2.3522}},
+		{{Latitude: 35.6895, Longitude: 139.6917}, {Latitude: -33.8651, Longitude: 151.2099}},
+		{{Latitude: -22.9068, Longitude: -43.1729}, {Latitude: 30.0444, Longitude: 31.2357}},
+		{{Latitude: 64.1466, Longitude: -21.9426}, {Latitude: 61.2181, Longitude: -149.9003}},
+		{{Latitude: -36.8485, Longitude: 174.7633}, {Latitude: -17.7134, Longitude: -178.0650}},
+	}
+	for _, pair := range pairs {
+		from, to := pair[0], pair[1]
+		dest := from.Destination(from.InitialBearing(&to), from.DistanceTo(&to))
+		requireGeoPointInDelta(t, &to, dest, 1e-9)
+		require.InDelta(t, from.FinalBearing(&to), normalizeBearing(dest.InitialBearing(&from)+180), 1e-6)
+	}
+}
+
+func TestGeoPoint_Interpolate(t *testing.T) {
+	origin := GeoPoint{}
+	east := GeoPoint{Longitude: 90}
+	requireGeoPointInDelta(t, &GeoPoint{Longitude: 45}, origin.Midpoint(&east), 1e-12)
+	requireGeoPointInDelta(t, &origin, origin.Interpolate(&east, 0), 1e-12)
+	requireGeoPointInDelta(t, &east, origin.Interpolate(&east, 1), 1e-12)
+	requireGeoPointInDelta(t, &GeoPoint{Longitude: 120}, origin.Interpolate(&east, 4.0/3), 1e-12)
+	requireGeoPointInDelta(t, &origin, origin.Interpolate(&origin, 0.3), 0)
+
+	// Interpolated points lie on the path, at the given fraction of it.
+	berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+	tokyo := GeoPoint{Latitude: 35.6895, Longitude: 139.6917}
+	total := berlin.DistanceTo(&tokyo)
+	for _, fraction := range []float64{0.1, 0.25, 0.5, 0.9} {
+		p := berlin.Interpolate(&tokyo, fraction)
+		require.InDelta(t, fraction*total.Meters(), berlin.GreatCircleDistance(p), 1e-6)
+		require.InDelta(t, (1-fraction)*total.Meters(), p.GreatCircleDistance(&tokyo), 1e-6)
+		require.InDelta(t, 0, p.CrossTrackDistance(&berlin, &tokyo).Meters(), 1e-6)
+	}
+	requireGeoPointInDelta(t, berlin.Interpolate(&tokyo, 0.5), berlin.Midpoint(&tokyo), 0)
+	requireGeoPointInDelta(t, berlin.Destination(berlin.InitialBearing(&tokyo), total/2), berlin.Midpoint(&tokyo), 1e-9)
+}
+
+func TestGeoPoint_TrackDistance(t *testing.T) {
+	// Along the equator, eastward: north is to the left.
+	start, end := GeoPoint{}, GeoPoint{Longitude: 90}
+	degreeLength := earthRadius * math.Pi / 180
+	north := GeoPoint{Latitude: 10, Longitude: 45}
+	require.InDelta(t, -10*degreeLength, north.CrossTrackDistance(&start, &end).Meters(), 1e-6)
+	require.InDelta(t, 45*degreeLength, north.AlongTrackDistance(&start, &end).Meters(), 1e-6)
+	south := GeoPoint{Latitude: -10, Longitude: -5}
+	require.InDelta(t, 10*degreeLength, south.CrossTrackDistance(&start, &end).Meters(), 1e-6)
+	require.InDelta(t, -5*degreeLength, south.AlongTrackDistance(&start, &end).Meters(), 1e-6)
+
+	// The closest point of the path is at the along-track distance from
+	// start, and at the cross-track distance from the point.
+	berlin := GeoPoint{Latitude: 52.5200, Longitude: 13.4050}
+	moscow := GeoPoint{Latitude: 55.7558, Longitude: 37.6176}
+	warsaw := GeoPoint{Latitude: 52.2297, Longitude: 21.0122}
+	closest := berlin.Destination(berlin.InitialBearing(&moscow), warsaw.AlongTrackDistance(&berlin, &moscow))
+	require.InDelta(t, math.Abs(warsaw.CrossTrackDistance(&berlin, &moscow).Meters()), warsaw.GreatCircleDistance(closest), 1e-6)
+	require.Positive(t, warsaw.CrossTrackDistance(&berlin, &moscow).Meters())
+}
@@ -0,0 +1,213 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// MaxGeohashPrecision is the largest geohash precision, whose cells measure
+// about 37 by 19 millimeters at the equator.
+const MaxGeohashPrecision = 12
+
+// ErrInvalidGeohash is returned when decoding a string that is not a geohash.
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash returns the geohash of g with the given number of characters, from
+// 1 to MaxGeohashPrecision. Each character divides the cell of the previous
+// ones in 32, so that points sharing a geohash prefix are close: 5 characters
+// identify a cell of about 4.9 by 4.9 kilometers, 7 characters a cell of
+// about 153 by 153 meters. Geohash panics if precision is out of range.
+//
+// Example:
+//
+//	p := GeoPoint{Latitude: 57.64911, Longitude: 10.40744}
+//	key := GenRedisKey("places", p.Geohash(6)) // places:u4pruy
+func (g *GeoPoint) Geohash(precision int) string {
+	if precision < 1 || precision > MaxGeohashPrecision {
+		panic(fmt.Sprintf("gobag: geohash precision %d not in [1, %d]", precision, MaxGeohashPrecision))
+	}
+	return geohashCellOf(g.Latitude, g.Longitude, precision).String()
+}
+
+// DecodeGeohash returns the cell identified by a geohash, as its center and
+// its bounding box. Geohashes are case-insensitive. It returns
+// ErrInvalidGeohash if hash is empty, longer than MaxGeohashPrecision or holds
+// a character outside the geohash alphabet.
+func DecodeGeohash(hash string) (*GeoPoint, BoundingBox, error) {
+	cell, err := parseGeohash(hash)
+	if err != nil {
+		return nil, BoundingBox{}, err
+	}
+	box := cell.box()
+	return NewGeoPoint((box.South+box.North)/2, (box.West+box.East)/2), box, nil
+}
+
+// GeohashNeighbors returns the geohashes of the cells adjacent to the cell of
+// hash, of the same precision, clockwise from north: north, northeast, east,
+// southeast, south, southwest, west and northwest. Neighbors wrap around the
+// antimeridian, but cells beyond a pole do not exist and are left out. It
+// returns ErrInvalidGeohash if hash is not a valid geohash.
+func GeohashNeighbors(hash string) ([]string, error) {
+	cell, err := parseGeohash(hash)
+	if err != nil {
+		return nil, err
+	}
+	rows, columns := cell.gridSize()
+	offsets := [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	neighbors := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		row := int64(cell.row) + int64(offset[0])
+		if row < 0 || row >= int64(rows) {
+			continue
+		}
+		column := (cell.column + columns + uint64(offset[1])) % columns
+		neighbor := geohashCell{row: uint64(row), column: column, precision: cell.precision}
+		neighbors = append(neighbors, neighbor.String())
+	}
+	return neighbors, nil
+}
+
+// GeohashCover returns the sorted geohashes of the given precision whose cells
+// intersect the circle of the given radius around center, on the spherical
+// Earth of GreatCircleDistance. Every point within radius of center has a
+// geohash starting with one of them, which makes them the keys to look up for
+// a proximity query on locations bucketed by geohash. The number of cells
+// grows with the square of the radius over the cell size, so the precision
+// should be chosen so that cells are not much smaller than the radius.
+// GeohashCover panics if precision is not in [1, MaxGeohashPrecision].
+//
+// Example:
+//
+//	center := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+//	for _, hash := range GeohashCover(&center, 2*Kilometer, 5) {
+//		keys = append(keys, GenRedisKey("places", hash))
+//	}
+func GeohashCover(center *GeoPoint, radius Distance, precision int) []string {
+	if precision < 1 || precision > MaxGeohashPrecision {
+		panic(fmt.Sprintf("gobag: geohash precision %d not in [1, %d]", precision, MaxGeohashPrecision))
+	}
+	box := BoundingBoxAround(center, radius)
+	southwest := geohashCellOf(box.South, box.West, precision)
+	northeast := geohashCellOf(box.North, box.East, precision)
+	_, columns := southwest.gridSize()
+	// Count the columns from west to east, wrapping around the antimeridian.
+	span := (northeast.column + columns - southwest.column) % columns
+	if box.West > box.East && northeast.column >= southwest.column {
+		// The box wraps around the world into its first column.
+		span = columns - 1
+	}
+
+	var hashes []string
+	for row := southwest.row; row <= northeast.row; row++ {
+		for i := uint64(0); i <= span; i++ {
+			cell := geohashCell{row: row, column: (southwest.column + i) % columns, precision: precision}
+			if cell.box().distanceTo(center) <= radius.Meters() {
+				hashes = append(hashes, cell.String())
+			}
+		}
+	}
+	slices.Sort(hashes)
+	return hashes
+}
+
+// geohashCell is the cell of a geohash, identified by its row from the south
+// and its column from the west in the grid of the cells of its precision.
+// The bits of a geohash alternate between the column, starting with its most
+// significant bit, and the row.
+type geohashCell struct {
+	row, column uint64
+	precision   int
+}
+
+func geohashCellOf(latitude, longitude float64, precision int) geohashCell {
+	cell := geohashCell{precision: precision}
+	rows, columns := cell.gridSize()
+	latitude = max(-90, min(90, latitude))
+	longitude = normalizeLongitude(longitude)
+	// Points on the northern or eastern edge of the grid belong to its last
+	// cells.
+	cell.row = min(uint64(math.Floor((latitude+90)/180*float64(rows))), rows-1)
+	cell.column = min(uint64(math.Floor((longitude+180)/360*float64(columns))), columns-1)
+	return cell
+}
+
+func parseGeohash(hash string) (geohashCell, error) {
+	if len(hash) == 0 || len(hash) > MaxGeohashPrecision {
+		return geohashCell{}, fmt.Errorf("%w: %q", ErrInvalidGeohash, hash)
+	}
+	cell := geohashCell{precision: len(hash)}
+	bit := 0
+	for i := 0; i < len(hash); i++ {
+		value := strings.IndexByte(geohashAlphabet, toLowerASCII(hash[i]))
+		if value < 0 {
+			return geohashCell{}, fmt.Errorf("%w: %q", ErrInvalidGeohash, hash)
+		}
+		for shift := 4; shift >= 0; shift-- {
+			b := uint64(value>>shift) & 1
+			if bit%2 == 0 {
+				cell.column = cell.column<<1 | b
+			} else {
+				cell.row = cell.row<<1 | b
+			}
+			bit++
+		}
+	}
+	return cell, nil
+}
+
+// String returns the geohash of the cell.
+func (c geohashCell) String() string {
+	rowBits, columnBits := c.gridBits()
+	hash := make([]byte, c.precision)
+	for i := range hash {
+		value := 0
+		for bit := 5 * i; bit < 5*i+5; bit++ {
+			var b uint64
+			if bit%2 == 0 {
+				columnBits--
+				b = c.column >> columnBits & 1
+			} else {
+				rowBits--
+				b = c.row >> rowBits & 1
+			}
+			value = value<<1 | int(b)
+		}
+		hash[i] = geohashAlphabet[value]
+	}
+	return string(hash)
+}
+
+// gridBits returns the number of bits of the row and the column of the cell.
+func (c geohashCell) gridBits() (rowBits, columnBits int) {
+	bits := 5 * c.precision
+	return bits / 2, bits - bits/2
+}
+
+// gridSize returns the number of rows and columns of the grid of the cell.
+func (c geohashCell) gridSize() (rows, columns uint64) {
+	rowBits, columnBits := c.gridBits()
+	return 1 << rowBits, 1 << columnBits
+}
+
+func (c geohashCell) box() BoundingBox {
+	rows, columns := c.gridSize()
+	height, width := 180/float64(rows), 360/float64(columns)
+	return BoundingBox{
+		South: -90 + float64(c.row)*height,
+		West:  -180 + float64(c.column)*width,
+		North: -90 + float64(c.row+1)*height,
+		East:  -180 + float64(c.column+1)*width,
+	}
+}
+
+func toLowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
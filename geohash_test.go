@@ -0,0 +1,132 @@
+package gobag
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoPoint_Geohash(t *testing.T) {
+	p := GeoPoint{Latitude: 57.64911, Longitude: 10.40744}
+	require.Equal(t, "u4pruydqqvj", p.Geohash(11))
+	require.Equal(t, "u4pru", p.Geohash(5))
+	require.Equal(t, "u", p.Geohash(1))
+
+	require.Equal(t, "ezs42", (&GeoPoint{Latitude: 42.605, Longitude: -5.603}).Geohash(5))
+	require.Equal(t, "s0000", (&GeoPoint{}).Geohash(5))
+	require.Equal(t, "000000", (&GeoPoint{Latitude: -90, Longitude: -180}).Geohash(6))
+	require.Equal(t, "zzzzzz", (&GeoPoint{Latitude: 90, Longitude: 180}).Geohash(6))
+	require.Equal(t, (&GeoPoint{Longitude: 170}).Geohash(8), (&GeoPoint{Longitude: -190}).Geohash(8))
+
+	require.Panics(t, func() { p.Geohash(0) })
+	require.Panics(t, func() { p.Geohash(MaxGeohashPrecision + 1) })
+}
+
+func TestDecodeGeohash(t *testing.T) {
+	center, box, err := DecodeGeohash("ezs42")
+	require.NoError(t, err)
+	require.Equal(t, GeoPoint{Latitude: 42.60498046875, Longitude: -5.60302734375}, *center)
+	require.Equal(t, BoundingBox{South: 42.5830078125, West: -5.625, North: 42.626953125, East: -5.5810546875}, box)
+
+	upper, _, err := DecodeGeohash("EZS42")
+	require.NoError(t, err)
+	require.Equal(t, center, upper)
+
+	// Decoding the geohash of a point returns a cell around it, whose center
+	// has the same geohash.
+	r := newTestRand()
+	for range 1000 {
+		p := GeoPoint{Latitude: r.Float64()*180 - 90, Longitude: r.Float64()*360 - 180}
+		precision := 1 + r.IntN(MaxGeohashPrecision)
+		hash := p.Geohash(precision)
+		center, box, err := DecodeGeohash(hash)
+		require.NoError(t, err)
+		require.True(t, box.South <= p.Latitude && p.Latitude <= box.North, "%v in %v", p, box)
+		require.True(t, box.West <= p.Longitude && p.Longitude <= box.East, "%v in %v", p, box)
+		require.Equal(t, hash, center.Geohash(precision))
+	}
+
+	for _, hash := range []string{"", "ezs4a", "ilo", "u4pruydqqvjx0"} {
+		_, _, err := DecodeGeohash(hash)
+		require.ErrorIs(t, err, ErrInvalidGeohash, "hash %q", hash)
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	// The southwestern cell of the world: nothing lies south of it, and its
+	// western neighbors wrap around the antimeridian.
+	neighbors, err := GeohashNeighbors("0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "3", "1", "p", "r"}, neighbors)
+
+	neighbors, err = GeohashNeighbors("u4pruyd")
+	require.NoError(t, err)
+	require.Len(t, neighbors, 8)
+	_, box, err := DecodeGeohash("u4pruyd")
+	require.NoError(t, err)
+	height, width := box.North-box.South, box.East-box.West
+	offsets := [8][2]float64{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	for i, neighbor := range neighbors {
+		center, _, err := DecodeGeohash(neighbor)
+		require.NoError(t, err)
+		expected := GeoPoint{
+			Latitude:  (box.South+box.North)/2 + offsets[i][0]*height,
+			Longitude: (box.West+box.East)/2 + offsets[i][1]*width,
+		}
+		requireGeoPointInDelta(t, &expected, center, 1e-9)
+	}
+
+	_, err = GeohashNeighbors("a")
+	require.ErrorIs(t, err, ErrInvalidGeohash)
+}
+
+func TestGeohashCover(t *testing.T) {
+	testCases := []struct {
+		name      string
+		center    GeoPoint
+		radius    Distance
+		precision int
+	}{
+		{"city", GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, 2 * Kilometer, 5},
+		{"small radius", GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, 10 * Meter, 5},
+		{"antimeridian", GeoPoint{Latitude: -16.5, Longitude: 179.99}, 30 * Kilometer, 4},
+		{"pole", GeoPoint{Latitude: 89.9, Longitude: 45}, 50 * Kilometer, 3},
+		{"wide", GeoPoint{Latitude: 10, Longitude: -100}, 3000 * Kilometer, 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cover := GeohashCover(&tc.center, tc.radius, tc.precision)
+			require.True(t, slices.IsSorted(cover))
+			require.Contains(t, cover, tc.center.Geohash(tc.precision))
+
+			// Every point within the radius falls in a covering cell.
+			r := rand.New(rand.NewPCG(uint64(len(tc.name)), 7))
+			for range 2000 {
+				p := tc.center.Destination(r.Float64()*360, Distance(r.Float64())*tc.radius)
+				require.Contains(t, cover, p.Geohash(tc.precision), "point %v", *p)
+			}
+
+			// Neighbors left out of the cover have their center beyond the radius.
+			for _, hash := range cover {
+				neighbors, err := GeohashNeighbors(hash)
+				require.NoError(t, err)
+				for _, neighbor := range neighbors {
+					if slices.Contains(cover, neighbor) {
+						continue
+					}
+					center, _, err := DecodeGeohash(neighbor)
+					require.NoError(t, err)
+					require.Greater(t, center.DistanceTo(&tc.center), tc.radius, "neighbor %s", neighbor)
+				}
+			}
+		})
+	}
+
+	// A radius within a single cell is covered by that cell alone.
+	center, _, err := DecodeGeohash("u09tvw")
+	require.NoError(t, err)
+	require.Equal(t, []string{"u09tvw"}, GeohashCover(center, Meter, 6))
+	require.Panics(t, func() { GeohashCover(center, Meter, 0) })
+}
@@ -0,0 +1,112 @@
+package gobag
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrInvalidEdges is returned by NewHistogram when the bucket edges are not
+// finite and strictly increasing, or fewer than two.
+var ErrInvalidEdges = errors.New("histogram edges must be at least two finite, strictly increasing values")
+
+// Histogram counts values into buckets delimited by edges: bucket i counts
+// the values v with Edges[i] <= v < Edges[i+1], except for the last bucket,
+// which also counts the values equal to the last edge. Values outside the
+// edges are counted in Underflow or Overflow, and NaN values are ignored.
+type Histogram struct {
+	// Edges holds the len(Counts)+1 bucket edges in increasing order.
+	Edges []float64
+	// Counts holds the number of values in each bucket.
+	Counts    []int
+	Underflow int
+	Overflow  int
+}
+
+// NewHistogram creates an empty Histogram with the given bucket edges, as
+// built for instance by LinearEdges or ExponentialEdges. It returns
+// ErrInvalidEdges if edges are not at least two finite, strictly increasing
+// values.
+func NewHistogram(edges []float64) (*Histogram, error) {
+	if len(edges) < 2 {
+		return nil, ErrInvalidEdges
+	}
+	for i, e := range edges {
+		if math.IsNaN(e) || math.IsInf(e, 0) || i > 0 && e <= edges[i-1] {
+			return nil, ErrInvalidEdges
+		}
+	}
+	return &Histogram{Edges: slices.Clone(edges), Counts: make([]int, len(edges)-1)}, nil
+}
+
+// HistogramOf returns a Histogram with the given bucket edges counting the
+// elements of s.
+//
+// Example:
+//
+//	h, err := gobag.HistogramOf(latenciesMs, gobag.ExponentialEdges(1, 2, 12))
+func HistogramOf[T Number](s []T, edges []float64) (*Histogram, error) {
+	h, err := NewHistogram(edges)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range s {
+		h.Add(float64(v))
+	}
+	return h, nil
+}
+
+// Add counts value in its bucket.
+func (h *Histogram) Add(value float64) {
+	last := len(h.Edges) - 1
+	switch {
+	case math.IsNaN(value):
+	case value < h.Edges[0]:
+		h.Underflow++
+	case value > h.Edges[last]:
+		h.Overflow++
+	case value == h.Edges[last]:
+		h.Counts[last-1]++
+	default:
+		i, found := slices.BinarySearch(h.Edges, value)
+		if !found {
+			// Edges[i] is the upper edge of the bucket.
+			i--
+		}
+		h.Counts[i]++
+	}
+}
+
+// Total returns the number of values counted, including those outside the
+// edges.
+func (h *Histogram) Total() int {
+	return Sum(h.Counts) + h.Underflow + h.Overflow
+}
+
+// LinearEdges returns n+1 edges delimiting n buckets of equal width between
+// lo and hi. It panics if n is less than 1.
+func LinearEdges(lo, hi float64, n int) []float64 {
+	if n < 1 {
+		panic("gobag: bucket count must be at least 1")
+	}
+	edges := make([]float64, n+1)
+	for i := range edges {
+		edges[i] = lo + (hi-lo)*float64(i)/float64(n)
+	}
+	return edges
+}
+
+// ExponentialEdges returns n+1 edges delimiting n buckets, the first edge
+// being start and each next one factor times the previous one. It panics if n
+// is less than 1.
+func ExponentialEdges(start, factor float64, n int) []float64 {
+	if n < 1 {
+		panic("gobag: bucket count must be at least 1")
+	}
+	edges := make([]float64, n+1)
+	edges[0] = start
+	for i := 1; i <= n; i++ {
+		edges[i] = edges[i-1] * factor
+	}
+	return edges
+}
@@ -0,0 +1,46 @@
+package gobag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("buckets", func(t *testing.T) {
+		h, err := HistogramOf([]float64{-1, 0, 0.5, 1, 2.5, 3, 3.5, math.NaN()}, []float64{0, 1, 2, 3})
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 1, 2}, h.Counts)
+		require.Equal(t, 1, h.Underflow)
+		require.Equal(t, 1, h.Overflow)
+		require.Equal(t, 7, h.Total())
+	})
+
+	t.Run("edges", func(t *testing.T) {
+		require.Equal(t, []float64{0, 2.5, 5, 7.5, 10}, LinearEdges(0, 10, 4))
+		require.Equal(t, []float64{1, 2, 4, 8}, ExponentialEdges(1, 2, 3))
+		require.Panics(t, func() { LinearEdges(0, 1, 0) })
+		require.Panics(t, func() { ExponentialEdges(1, 2, 0) })
+
+		h, err := HistogramOf([]int{1, 3, 5, 50, 700}, ExponentialEdges(1, 10, 3))
+		require.NoError(t, err)
+		require.Equal(t, []int{3, 1, 1}, h.Counts)
+	})
+
+	t.Run("invalid edges", func(t *testing.T) {
+		for _, edges := range [][]float64{nil, {1}, {1, 1}, {2, 1}, {0, math.Inf(1)}, {math.NaN(), 1}} {
+			_, err := NewHistogram(edges)
+			require.ErrorIs(t, err, ErrInvalidEdges, "%v", edges)
+		}
+	})
+
+	t.Run("edges are copied", func(t *testing.T) {
+		edges := []float64{0, 1}
+		h, err := NewHistogram(edges)
+		require.NoError(t, err)
+		edges[0] = 5
+		h.Add(0)
+		require.Equal(t, []int{1}, h.Counts)
+	})
+}
@@ -0,0 +1,173 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// PanicError is the error reported by ParallelMap and ParallelForEach when the
+// function applied to an element panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error returns the panic value formatted as an error message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is and
+// errors.As can inspect it.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// ParallelOption configures the behavior of ParallelMap and ParallelForEach.
+type ParallelOption func(*parallelConfig)
+
+type parallelConfig struct {
+	collectAllErrors bool
+}
+
+// WithCollectAllErrors makes ParallelMap and ParallelForEach process every
+// element even when some of them fail, and report all failures joined with
+// errors.Join instead of cancelling the remaining work on the first one.
+func WithCollectAllErrors() ParallelOption {
+	return func(c *parallelConfig) {
+		c.collectAllErrors = true
+	}
+}
+
+// ParallelMap applies fn to every element of in using at most limit
+// goroutines, and returns the results in the same order as in. A limit less
+// than 1 runs every element concurrently. A panic in fn is recovered and
+// reported as a *PanicError. Each error is wrapped with the index of the
+// element that caused it.
+//
+// By default the first error cancels the context passed to the remaining
+// calls, no further element is started, and ParallelMap returns nil results
+// along with that error. With WithCollectAllErrors every element is processed
+// and ParallelMap returns all results, holding the zero value of R for failed
+// elements, along with the joined errors.
+//
+// If ctx is cancelled before every element is processed, its error is
+// reported as well.
+//
+// Example:
+//
+//	responses, err := ParallelMap(ctx, urls, 8, func(ctx context.Context, u string) (*http.Response, error) {
+//		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return client.Do(req)
+//	})
+func ParallelMap[T, R any](
+	ctx context.Context,
+	in []T,
+	limit int,
+	fn func(context.Context, T) (R, error),
+	opts ...ParallelOption,
+) ([]R, error) {
+	var config parallelConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if in == nil {
+		return nil, nil
+	}
+
+	workers := limit
+	if workers < 1 || workers > len(in) {
+		workers = len(in)
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results   = make([]R, len(in))
+		errs      = make([]error, len(in))
+		firstErr  error
+		errOnce   sync.Once
+		next      atomic.Int64
+		completed atomic.Int64
+		wg        sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for workCtx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(in) {
+					return
+				}
+				result, err := callRecovering(workCtx, fn, in[i])
+				completed.Add(1)
+				if err == nil {
+					results[i] = result
+					continue
+				}
+				errs[i] = fmt.Errorf("element %d: %w", i, err)
+				if !config.collectAllErrors {
+					errOnce.Do(func() {
+						firstErr = errs[i]
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var ctxErr error
+	if int(completed.Load()) < len(in) {
+		ctxErr = ctx.Err()
+	}
+
+	if !config.collectAllErrors {
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		if ctxErr != nil {
+			return nil, ctxErr
+		}
+		return results, nil
+	}
+	return results, errors.Join(append(errs, ctxErr)...)
+}
+
+// ParallelForEach calls fn for every element of in using at most limit
+// goroutines. It follows the same concurrency, cancellation, panic and error
+// reporting rules as ParallelMap.
+func ParallelForEach[T any](
+	ctx context.Context,
+	in []T,
+	limit int,
+	fn func(context.Context, T) error,
+	opts ...ParallelOption,
+) error {
+	_, err := ParallelMap(ctx, in, limit, func(ctx context.Context, element T) (struct{}, error) {
+		return struct{}{}, fn(ctx, element)
+	}, opts...)
+	return err
+}
+
+func callRecovering[T, R any](ctx context.Context, fn func(context.Context, T) (R, error), element T) (result R, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx, element)
+}
@@ -0,0 +1,141 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelMap(t *testing.T) {
+	t.Run("preserves order and respects the limit", func(t *testing.T) {
+		in := make([]int, 50)
+		for i := range in {
+			in[i] = i
+		}
+
+		var running, maxRunning atomic.Int64
+		results, err := ParallelMap(context.Background(), in, 4, func(_ context.Context, i int) (int, error) {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				observed := maxRunning.Load()
+				if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return i * i, nil
+		})
+		require.NoError(t, err)
+		require.LessOrEqual(t, maxRunning.Load(), int64(4))
+		for i := range in {
+			require.Equal(t, i*i, results[i])
+		}
+	})
+
+	t.Run("nil and empty input", func(t *testing.T) {
+		square := func(_ context.Context, i int) (int, error) { return i * i, nil }
+
+		results, err := ParallelMap(context.Background(), nil, 2, square)
+		require.NoError(t, err)
+		require.Nil(t, results)
+
+		results, err = ParallelMap(context.Background(), []int{}, 2, square)
+		require.NoError(t, err)
+		require.Equal(t, []int{}, results)
+	})
+
+	t.Run("cancels remaining work on first error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		var started atomic.Int64
+		in := make([]int, 100)
+		for i := range in {
+			in[i] = i
+		}
+
+		results, err := ParallelMap(context.Background(), in, 2, func(ctx context.Context, i int) (int, error) {
+			started.Add(1)
+			if i == 3 {
+				return 0, errBoom
+			}
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(time.Millisecond):
+				return i, nil
+			}
+		})
+		require.ErrorIs(t, err, errBoom)
+		require.ErrorContains(t, err, "element 3")
+		require.Nil(t, results)
+		require.Less(t, started.Load(), int64(len(in)))
+	})
+
+	t.Run("collects all errors", func(t *testing.T) {
+		errOdd := errors.New("odd")
+		results, err := ParallelMap(context.Background(), []int{1, 2, 3, 4}, 2, func(_ context.Context, i int) (int, error) {
+			if i%2 == 1 {
+				return 0, errOdd
+			}
+			return i * 10, nil
+		}, WithCollectAllErrors())
+		require.ErrorIs(t, err, errOdd)
+		require.ErrorContains(t, err, "element 0")
+		require.ErrorContains(t, err, "element 2")
+		require.Equal(t, []int{0, 20, 0, 40}, results)
+	})
+
+	t.Run("recovers panics", func(t *testing.T) {
+		_, err := ParallelMap(context.Background(), []int{1, 2}, 1, func(_ context.Context, i int) (int, error) {
+			if i == 2 {
+				panic("kaboom")
+			}
+			return i, nil
+		})
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, "kaboom", panicErr.Value)
+		require.NotEmpty(t, panicErr.Stack)
+
+		errCause := errors.New("cause")
+		_, err = ParallelMap(context.Background(), []int{1}, 1, func(_ context.Context, _ int) (int, error) {
+			panic(errCause)
+		})
+		require.ErrorIs(t, err, errCause)
+	})
+
+	t.Run("reports parent context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := ParallelMap(ctx, []int{1, 2, 3}, 1, func(_ context.Context, i int) (int, error) {
+			return i, nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = ParallelMap(ctx, []int{1, 2, 3}, 1, func(_ context.Context, i int) (int, error) {
+			return i, nil
+		}, WithCollectAllErrors())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestParallelForEach(t *testing.T) {
+	var sum atomic.Int64
+	err := ParallelForEach(context.Background(), []int64{1, 2, 3, 4}, 0, func(_ context.Context, i int64) error {
+		sum.Add(i)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), sum.Load())
+
+	errBoom := errors.New("boom")
+	err = ParallelForEach(context.Background(), []int64{1}, 1, func(_ context.Context, _ int64) error {
+		return errBoom
+	})
+	require.ErrorIs(t, err, errBoom)
+}
@@ -0,0 +1,116 @@
+package gobag
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Median returns the median of the elements of s, which is the mean of its
+// two middle elements when s has an even length, or NaN if s is empty. It is
+// Percentile(s, 50).
+func Median[T Number](s []T) float64 {
+	return Percentile(s, 50)
+}
+
+// Percentile returns the p-th percentile of the elements of s, for p in
+// [0, 100], interpolating linearly between the two closest ranks as the
+// default method of NumPy and Excel's PERCENTILE.INC do. It returns NaN if s
+// is empty or p is out of range.
+//
+// Percentile leaves s untouched and runs in linear time on average by
+// selecting the ranks it needs in a copy of s rather than sorting it. To
+// compute several percentiles of the same elements, use Percentiles.
+func Percentile[T Number](s []T, p float64) float64 {
+	if len(s) == 0 || !(p >= 0 && p <= 100) {
+		return math.NaN()
+	}
+	c := slices.Clone(s)
+	rank, fraction := percentileRank(len(c), p)
+	selectNth(c, rank)
+	value := float64(c[rank])
+	if fraction > 0 {
+		// Selection left the elements ranking after rank past it.
+		value += fraction * (float64(slices.Min(c[rank+1:])) - value)
+	}
+	return value
+}
+
+// Percentiles returns the percentiles of the elements of s for each of ps,
+// as computed by Percentile, sorting a copy of s once.
+//
+// Example:
+//
+//	latencies := gobag.Percentiles(durations, 50, 95, 99)
+func Percentiles[T Number](s []T, ps ...float64) []float64 {
+	values := make([]float64, len(ps))
+	var sorted []T
+	for i, p := range ps {
+		if len(s) == 0 || !(p >= 0 && p <= 100) {
+			values[i] = math.NaN()
+			continue
+		}
+		if sorted == nil {
+			sorted = slices.Clone(s)
+			slices.Sort(sorted)
+		}
+		rank, fraction := percentileRank(len(sorted), p)
+		values[i] = float64(sorted[rank])
+		if fraction > 0 {
+			values[i] += fraction * (float64(sorted[rank+1]) - values[i])
+		}
+	}
+	return values
+}
+
+// percentileRank returns the rank, starting at zero, of the p-th percentile
+// of n sorted elements, as an integer part and a fractional part.
+func percentileRank(n int, p float64) (int, float64) {
+	rank, fraction := math.Modf(p / 100 * float64(n-1))
+	return int(rank), fraction
+}
+
+// selectNth partially sorts s so that s[n] holds the element it would hold if
+// s were sorted, every element before it being less or equal and every
+// element after it greater or equal. It is Hoare's quickselect with a median
+// of three pivot.
+func selectNth[T cmp.Ordered](s []T, n int) {
+	lo, hi := 0, len(s)-1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if cmp.Less(s[mid], s[lo]) {
+			s[mid], s[lo] = s[lo], s[mid]
+		}
+		if cmp.Less(s[hi], s[lo]) {
+			s[hi], s[lo] = s[lo], s[hi]
+		}
+		if cmp.Less(s[hi], s[mid]) {
+			s[hi], s[mid] = s[mid], s[hi]
+		}
+		pivot := s[mid]
+
+		i, j := lo, hi
+		for i <= j {
+			for cmp.Less(s[i], pivot) {
+				i++
+			}
+			for cmp.Less(pivot, s[j]) {
+				j--
+			}
+			if i <= j {
+				s[i], s[j] = s[j], s[i]
+				i++
+				j--
+			}
+		}
+		// s[lo:j+1] <= pivot <= s[i:hi+1], and s[j+1:i] holds the pivot.
+		switch {
+		case n <= j:
+			hi = j
+		case n >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
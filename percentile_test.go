@@ -0,0 +1,73 @@
+package gobag
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	t.Run("interpolates between ranks", func(t *testing.T) {
+		s := []int{15, 20, 35, 40, 50}
+		require.Equal(t, 35.0, Median(s))
+		require.Equal(t, 15.0, Percentile(s, 0))
+		require.Equal(t, 50.0, Percentile(s, 100))
+		require.Equal(t, 29.0, Percentile(s, 40))
+		require.InDelta(t, 48.0, Percentile(s, 95), 1e-9)
+		require.Equal(t, []int{15, 20, 35, 40, 50}, s)
+
+		require.Equal(t, 2.5, Median([]float64{4, 1, 3, 2}))
+		require.Equal(t, 7.0, Median([]uint8{7}))
+	})
+
+	t.Run("durations", func(t *testing.T) {
+		latencies := []time.Duration{30 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond}
+		require.Equal(t, float64(20*time.Millisecond), Median(latencies))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		require.True(t, math.IsNaN(Median([]int{})))
+		require.True(t, math.IsNaN(Percentile([]int{1}, -1)))
+		require.True(t, math.IsNaN(Percentile([]int{1}, 101)))
+		require.True(t, math.IsNaN(Percentile([]int{1}, math.NaN())))
+		values := Percentiles([]int{1, 2}, 50, 200)
+		require.Equal(t, 1.5, values[0])
+		require.True(t, math.IsNaN(values[1]))
+		require.True(t, math.IsNaN(Percentiles([]int{}, 50)[0]))
+	})
+
+	t.Run("selection matches sorting", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(5, 6))
+		for _, n := range []int{1, 2, 3, 10, 101, 1000} {
+			s := make([]int, n)
+			for i := range s {
+				s[i] = r.IntN(50)
+			}
+			ps := []float64{0, 1, 25, 50, 90, 99, 99.9, 100}
+			expected := Percentiles(s, ps...)
+			for i, p := range ps {
+				require.InDelta(t, expected[i], Percentile(s, p), 1e-9, "n=%d p=%v", n, p)
+			}
+		}
+	})
+
+	t.Run("select nth", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(7, 8))
+		s := make([]int, 500)
+		for i := range s {
+			s[i] = r.IntN(100)
+		}
+		sorted := slices.Sorted(slices.Values(s))
+		for _, n := range []int{0, 1, 250, 498, 499} {
+			c := slices.Clone(s)
+			selectNth(c, n)
+			require.Equal(t, sorted[n], c[n])
+			require.LessOrEqual(t, slices.Max(c[:n+1]), c[n])
+			require.GreaterOrEqual(t, slices.Min(c[n:]), c[n])
+		}
+	})
+}
@@ -0,0 +1,393 @@
+package gobag
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+const (
+	// roaringArrayMax is the largest number of values a roaring container
+	// stores as a sorted array before switching to a bitmap, which then takes
+	// less memory.
+	roaringArrayMax = 4096
+	roaringWords    = (1 << 16) / 64
+)
+
+// RoaringBitset is a compressed set of ints in [0, 2^32), following the
+// Roaring bitmap layout: values are grouped by their upper 16 bits, and each
+// group is stored either as a sorted array of its lower 16 bits or, once it
+// holds more than 4096 values, as a 65536-bit bitmap. Sparse sets thus take
+// about two bytes per value however large the values, while dense groups get
+// the word-wise set operations of Bitset.
+//
+// The zero value of RoaringBitset is an empty set ready to use. A
+// RoaringBitset is not safe for concurrent use without external
+// synchronization.
+type RoaringBitset struct {
+	// containers is sorted by key.
+	containers []*roaringContainer
+}
+
+type roaringContainer struct {
+	key uint16
+	// Exactly one of array, sorted, and bitmap, of roaringWords words, is
+	// used.
+	array  []uint16
+	bitmap []uint64
+	count  int
+}
+
+// NewRoaringBitset creates a new RoaringBitset containing the given values.
+// It panics if a value is out of range.
+func NewRoaringBitset(values ...int) *RoaringBitset {
+	r := &RoaringBitset{}
+	for _, v := range values {
+		r.Set(v)
+	}
+	return r
+}
+
+// Set adds i to the set. It panics if i is not in [0, 2^32).
+func (r *RoaringBitset) Set(i int) {
+	if i < 0 || uint64(i) > math.MaxUint32 {
+		panic("gobag: roaring bitset index out of range")
+	}
+	key, low := uint16(i>>16), uint16(i)
+	j, found := r.find(key)
+	if !found {
+		r.containers = slices.Insert(r.containers, j, &roaringContainer{key: key})
+	}
+	c := r.containers[j]
+	if c.bitmap != nil {
+		if c.bitmap[low/64]&(1<<(low%64)) == 0 {
+			c.bitmap[low/64] |= 1 << (low % 64)
+			c.count++
+		}
+		return
+	}
+	k, found := slices.BinarySearch(c.array, low)
+	if found {
+		return
+	}
+	c.array = slices.Insert(c.array, k, low)
+	c.count++
+	c.normalize()
+}
+
+// Clear removes i from the set. Values that are not present are ignored.
+func (r *RoaringBitset) Clear(i int) {
+	if i < 0 || uint64(i) > math.MaxUint32 {
+		return
+	}
+	key, low := uint16(i>>16), uint16(i)
+	j, found := r.find(key)
+	if !found {
+		return
+	}
+	c := r.containers[j]
+	if c.bitmap != nil {
+		if c.bitmap[low/64]&(1<<(low%64)) == 0 {
+			return
+		}
+		c.bitmap[low/64] &^= 1 << (low % 64)
+	} else {
+		k, found := slices.BinarySearch(c.array, low)
+		if !found {
+			return
+		}
+		c.array = slices.Delete(c.array, k, k+1)
+	}
+	c.count--
+	if c.count == 0 {
+		r.containers = slices.Delete(r.containers, j, j+1)
+		return
+	}
+	c.normalize()
+}
+
+// Test reports whether i is present in the set.
+func (r *RoaringBitset) Test(i int) bool {
+	if i < 0 || uint64(i) > math.MaxUint32 {
+		return false
+	}
+	j, found := r.find(uint16(i >> 16))
+	return found && r.containers[j].has(uint16(i))
+}
+
+// Count returns the number of values in the set.
+func (r *RoaringBitset) Count() int {
+	count := 0
+	for _, c := range r.containers {
+		count += c.count
+	}
+	return count
+}
+
+// Reset removes every value from the set.
+func (r *RoaringBitset) Reset() {
+	clear(r.containers)
+	r.containers = r.containers[:0]
+}
+
+// Clone returns a copy of the set.
+func (r *RoaringBitset) Clone() *RoaringBitset {
+	clone := &RoaringBitset{containers: make([]*roaringContainer, len(r.containers))}
+	for i, c := range r.containers {
+		clone.containers[i] = c.clone()
+	}
+	return clone
+}
+
+// Equal reports whether r and other contain the same values.
+func (r *RoaringBitset) Equal(other *RoaringBitset) bool {
+	return slices.EqualFunc(r.containers, other.containers, func(a, b *roaringContainer) bool {
+		return a.key == b.key && a.count == b.count && slices.Equal(a.array, b.array) && slices.Equal(a.bitmap, b.bitmap)
+	})
+}
+
+// And returns a new RoaringBitset with the values present in both r and
+// other.
+func (r *RoaringBitset) And(other *RoaringBitset) *RoaringBitset {
+	return r.combine(other, roaringAnd)
+}
+
+// Or returns a new RoaringBitset with the values present in r, other, or
+// both.
+func (r *RoaringBitset) Or(other *RoaringBitset) *RoaringBitset {
+	return r.combine(other, roaringOr)
+}
+
+// Xor returns a new RoaringBitset with the values present in exactly one of r
+// and other.
+func (r *RoaringBitset) Xor(other *RoaringBitset) *RoaringBitset {
+	return r.combine(other, roaringXor)
+}
+
+// AndNot returns a new RoaringBitset with the values of r that are not
+// present in other.
+func (r *RoaringBitset) AndNot(other *RoaringBitset) *RoaringBitset {
+	return r.combine(other, roaringAndNot)
+}
+
+// All returns an iterator over the values of the set in ascending order. The
+// set must not be modified during the iteration.
+func (r *RoaringBitset) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, c := range r.containers {
+			high := int(c.key) << 16
+			for _, low := range c.array {
+				if !yield(high + int(low)) {
+					return
+				}
+			}
+			for i, w := range c.bitmap {
+				for w != 0 {
+					if !yield(high + i*64 + bits.TrailingZeros64(w)) {
+						return
+					}
+					w &= w - 1
+				}
+			}
+		}
+	}
+}
+
+// Ints returns the values of the set in ascending order, or nil if the set is
+// empty.
+func (r *RoaringBitset) Ints() []int {
+	count := r.Count()
+	if count == 0 {
+		return nil
+	}
+	values := make([]int, 0, count)
+	for v := range r.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Bitset returns a Bitset containing the values of the set.
+func (r *RoaringBitset) Bitset() *Bitset {
+	b := &Bitset{}
+	if len(r.containers) == 0 {
+		return b
+	}
+	last := r.containers[len(r.containers)-1]
+	b.words = make([]uint64, (int(last.key)+1)*roaringWords)
+	for _, c := range r.containers {
+		words := b.words[int(c.key)*roaringWords:][:roaringWords]
+		if c.bitmap != nil {
+			copy(words, c.bitmap)
+			continue
+		}
+		for _, low := range c.array {
+			words[low/64] |= 1 << (low % 64)
+		}
+	}
+	return b
+}
+
+func (r *RoaringBitset) find(key uint16) (int, bool) {
+	return slices.BinarySearchFunc(r.containers, key, func(c *roaringContainer, key uint16) int {
+		return int(c.key) - int(key)
+	})
+}
+
+// roaringOp describes a set operation by which values it keeps, and whether
+// it keeps the containers found in only one of its operands.
+type roaringOp struct {
+	keep      func(inA, inB bool) bool
+	word      func(x, y uint64) uint64
+	keepOnlyA bool
+	keepOnlyB bool
+}
+
+var (
+	roaringAnd = roaringOp{
+		keep: func(inA, inB bool) bool { return inA && inB },
+		word: func(x, y uint64) uint64 { return x & y },
+	}
+	roaringOr = roaringOp{
+		keep:      func(inA, inB bool) bool { return inA || inB },
+		word:      func(x, y uint64) uint64 { return x | y },
+		keepOnlyA: true,
+		keepOnlyB: true,
+	}
+	roaringXor = roaringOp{
+		keep:      func(inA, inB bool) bool { return inA != inB },
+		word:      func(x, y uint64) uint64 { return x ^ y },
+		keepOnlyA: true,
+		keepOnlyB: true,
+	}
+	roaringAndNot = roaringOp{
+		keep:      func(inA, inB bool) bool { return inA && !inB },
+		word:      func(x, y uint64) uint64 { return x &^ y },
+		keepOnlyA: true,
+	}
+)
+
+// combine merges the containers of r and other, which are sorted by key,
+// applying op to the pairs of containers sharing a key.
+func (r *RoaringBitset) combine(other *RoaringBitset, op roaringOp) *RoaringBitset {
+	result := &RoaringBitset{}
+	a, b := r.containers, other.containers
+	for len(a) > 0 || len(b) > 0 {
+		switch {
+		case len(b) == 0 || len(a) > 0 && a[0].key < b[0].key:
+			if op.keepOnlyA {
+				result.containers = append(result.containers, a[0].clone())
+			}
+			a = a[1:]
+		case len(a) == 0 || b[0].key < a[0].key:
+			if op.keepOnlyB {
+				result.containers = append(result.containers, b[0].clone())
+			}
+			b = b[1:]
+		default:
+			if c := combineContainers(a[0], b[0], op); c.count > 0 {
+				result.containers = append(result.containers, c)
+			}
+			a, b = a[1:], b[1:]
+		}
+	}
+	return result
+}
+
+func combineContainers(a, b *roaringContainer, op roaringOp) *roaringContainer {
+	c := &roaringContainer{key: a.key}
+	if a.bitmap == nil && b.bitmap == nil {
+		// Merge the sorted arrays.
+		i, j := 0, 0
+		for i < len(a.array) || j < len(b.array) {
+			var v uint16
+			var inA, inB bool
+			switch {
+			case j == len(b.array) || i < len(a.array) && a.array[i] < b.array[j]:
+				v, inA = a.array[i], true
+				i++
+			case i == len(a.array) || b.array[j] < a.array[i]:
+				v, inB = b.array[j], true
+				j++
+			default:
+				v, inA, inB = a.array[i], true, true
+				i++
+				j++
+			}
+			if op.keep(inA, inB) {
+				c.array = append(c.array, v)
+			}
+		}
+		c.count = len(c.array)
+		c.normalize()
+		return c
+	}
+
+	if a.bitmap == nil && !op.keep(false, true) {
+		// The result is a subset of the array of a: test its values.
+		for _, v := range a.array {
+			if op.keep(true, b.has(v)) {
+				c.array = append(c.array, v)
+			}
+		}
+		c.count = len(c.array)
+		return c
+	}
+
+	x, y := a.words(), b.words()
+	c.bitmap = make([]uint64, roaringWords)
+	for i := range c.bitmap {
+		c.bitmap[i] = op.word(x[i], y[i])
+		c.count += bits.OnesCount64(c.bitmap[i])
+	}
+	c.normalize()
+	return c
+}
+
+func (c *roaringContainer) has(low uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[low/64]&(1<<(low%64)) != 0
+	}
+	_, found := slices.BinarySearch(c.array, low)
+	return found
+}
+
+// words returns the bitmap of the container, building one for an array.
+func (c *roaringContainer) words() []uint64 {
+	if c.bitmap != nil {
+		return c.bitmap
+	}
+	words := make([]uint64, roaringWords)
+	for _, low := range c.array {
+		words[low/64] |= 1 << (low % 64)
+	}
+	return words
+}
+
+// normalize switches the container to the representation its count calls for.
+func (c *roaringContainer) normalize() {
+	switch {
+	case c.bitmap == nil && c.count > roaringArrayMax:
+		c.bitmap = c.words()
+		c.array = nil
+	case c.bitmap != nil && c.count <= roaringArrayMax:
+		c.array = make([]uint16, 0, c.count)
+		for i, w := range c.bitmap {
+			for w != 0 {
+				c.array = append(c.array, uint16(i*64+bits.TrailingZeros64(w)))
+				w &= w - 1
+			}
+		}
+		c.bitmap = nil
+	}
+}
+
+func (c *roaringContainer) clone() *roaringContainer {
+	return &roaringContainer{
+		key:    c.key,
+		array:  slices.Clone(c.array),
+		bitmap: slices.Clone(c.bitmap),
+		count:  c.count,
+	}
+}
@@ -0,0 +1,94 @@
+package gobag
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomRoaringValues returns values clustered in a few 65536-wide groups,
+// dense enough for some groups to be stored as bitmaps.
+func randomRoaringValues(r *rand.Rand, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		group := r.IntN(4)
+		spread := 1 << 16
+		if group == 0 {
+			spread = 6000
+		}
+		values[i] = group<<16 + r.IntN(spread)
+	}
+	return values
+}
+
+func TestRoaringBitset(t *testing.T) {
+	t.Run("set, clear and test", func(t *testing.T) {
+		var r RoaringBitset
+		r.Set(0)
+		r.Set(math.MaxUint32)
+		r.Set(70000)
+		r.Set(70000)
+		require.True(t, r.Test(0))
+		require.True(t, r.Test(math.MaxUint32))
+		require.True(t, r.Test(70000))
+		require.False(t, r.Test(70001))
+		require.False(t, r.Test(-1))
+		require.Equal(t, 3, r.Count())
+		require.Equal(t, []int{0, 70000, math.MaxUint32}, r.Ints())
+
+		r.Clear(70000)
+		r.Clear(70000)
+		r.Clear(-1)
+		require.Equal(t, []int{0, math.MaxUint32}, r.Ints())
+		require.Len(t, r.containers, 2)
+
+		r.Reset()
+		require.Nil(t, r.Ints())
+		require.Panics(t, func() { r.Set(-1) })
+		require.Panics(t, func() { r.Set(math.MaxUint32 + 1) })
+	})
+
+	t.Run("containers switch representation", func(t *testing.T) {
+		var r RoaringBitset
+		for i := range roaringArrayMax + 1 {
+			r.Set(i * 2)
+		}
+		require.NotNil(t, r.containers[0].bitmap)
+		r.Clear(0)
+		require.Nil(t, r.containers[0].bitmap)
+		require.Len(t, r.containers[0].array, roaringArrayMax)
+		require.Equal(t, roaringArrayMax, r.Count())
+	})
+
+	t.Run("algebra matches Set", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(3, 4))
+		for range 10 {
+			a := randomRoaringValues(rng, rng.IntN(20000))
+			b := randomRoaringValues(rng, rng.IntN(20000))
+			sa, sb := NewSet(a...), NewSet(b...)
+			ra, rb := NewRoaringBitset(a...), NewRoaringBitset(b...)
+
+			require.Equal(t, sortedSet(sa), ra.Ints())
+			require.Equal(t, sortedSet(sa.Intersect(sb)), ra.And(rb).Ints())
+			require.Equal(t, sortedSet(sa.Union(sb)), ra.Or(rb).Ints())
+			require.Equal(t, sortedSet(sa.SymmetricDifference(sb)), ra.Xor(rb).Ints())
+			require.Equal(t, sortedSet(sa.Difference(sb)), ra.AndNot(rb).Ints())
+			require.Equal(t, sortedSet(sb.Difference(sa)), rb.AndNot(ra).Ints())
+
+			require.True(t, ra.And(rb).Equal(NewRoaringBitset(sortedSet(sa.Intersect(sb))...)))
+			require.True(t, ra.Bitset().Equal(NewBitset(a...)))
+		}
+	})
+
+	t.Run("clone is independent", func(t *testing.T) {
+		r := NewRoaringBitset(1, 2, 3)
+		c := r.Clone()
+		c.Set(4)
+		c.Clear(1)
+		require.Equal(t, []int{1, 2, 3}, r.Ints())
+		require.Equal(t, []int{2, 3, 4}, c.Ints())
+		require.False(t, r.Equal(c))
+	})
+}
@@ -0,0 +1,251 @@
+package gobag
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// ErrInvalidWeights is returned by the weighted sampling functions when the
+// weights do not match the items, or are negative, not finite, or all zero.
+var ErrInvalidWeights = errors.New("invalid sampling weights")
+
+// sampled is an element picked by a sampling function, along with its
+// position in the input so that samples can keep the input order.
+type sampled[T any] struct {
+	index   int
+	element T
+}
+
+// ReservoirSample returns k elements drawn uniformly without replacement from
+// seq in a single pass, keeping their order in seq. It returns every element
+// if seq yields fewer than k. ReservoirSample panics if k is negative.
+//
+// Example:
+//
+//	r := rand.New(rand.NewPCG(42, 0))
+//	sample := gobag.ReservoirSample(r, slices.Values(users), 100)
+func ReservoirSample[T any](r *rand.Rand, seq iter.Seq[T], k int) []T {
+	if k < 0 {
+		panic("gobag: negative sample size")
+	}
+	reservoir := make([]sampled[T], 0, k)
+	i := 0
+	for element := range seq {
+		if i < k {
+			reservoir = append(reservoir, sampled[T]{index: i, element: element})
+		} else if j := r.IntN(i + 1); j < k {
+			reservoir[j] = sampled[T]{index: i, element: element}
+		}
+		i++
+	}
+	return sampledElements(reservoir)
+}
+
+// Shuffle shuffles the elements of s in place.
+func Shuffle[T any](r *rand.Rand, s []T) {
+	r.Shuffle(len(s), func(i, j int) {
+		s[i], s[j] = s[j], s[i]
+	})
+}
+
+// WeightedSample returns k elements drawn with replacement from items, each
+// draw picking items[i] with a probability proportional to weights[i]. It
+// builds an AliasSampler, so that every draw runs in O(1) time. WeightedSample
+// panics if k is negative.
+func WeightedSample[T any](r *rand.Rand, items []T, weights []float64, k int) ([]T, error) {
+	if k < 0 {
+		panic("gobag: negative sample size")
+	}
+	if len(items) != len(weights) {
+		return nil, ErrInvalidWeights
+	}
+	sampler, err := NewAliasSampler(weights)
+	if err != nil {
+		return nil, err
+	}
+	sample := make([]T, k)
+	for i := range sample {
+		sample[i] = items[sampler.Sample(r)]
+	}
+	return sample, nil
+}
+
+// WeightedSampleWithoutReplacement returns k distinct elements of items, in
+// the order in which they are drawn, each draw picking one of the remaining
+// items with a probability proportional to its weight. Items weighing zero are
+// never drawn, so fewer than k elements are returned if fewer than k weigh
+// more than zero. WeightedSampleWithoutReplacement panics if k is negative.
+func WeightedSampleWithoutReplacement[T any](r *rand.Rand, items []T, weights []float64, k int) ([]T, error) {
+	if k < 0 {
+		panic("gobag: negative sample size")
+	}
+	if len(items) != len(weights) {
+		return nil, ErrInvalidWeights
+	}
+	if _, err := validateWeights(weights); err != nil {
+		return nil, err
+	}
+
+	// Efraimidis and Spirakis: ranking the items by u^(1/w), for u uniform
+	// in (0, 1), draws them in the order of successive weighted draws. The
+	// logarithm of the key, ln(u)/w, preserves the ranking and the precision.
+	type keyed struct {
+		index int
+		key   float64
+	}
+	candidates := make([]keyed, 0, len(items))
+	for i, w := range weights {
+		if w > 0 {
+			u := 1 - r.Float64()
+			candidates = append(candidates, keyed{index: i, key: math.Log(u) / w})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b keyed) int {
+		return cmp.Compare(b.key, a.key)
+	})
+
+	sample := make([]T, 0, min(k, len(candidates)))
+	for _, c := range candidates[:cap(sample)] {
+		sample = append(sample, items[c.index])
+	}
+	return sample, nil
+}
+
+// StratifiedSample groups items by the key returned by the key function and
+// draws k elements uniformly without replacement from each group, keeping
+// their order in items. Groups holding fewer than k elements are returned
+// whole. StratifiedSample panics if k is negative.
+//
+// Example:
+//
+//	r := rand.New(rand.NewPCG(42, 0))
+//	perCountry := gobag.StratifiedSample(r, users, func(u User) string { return u.Country }, 50)
+func StratifiedSample[T any, K comparable](r *rand.Rand, items []T, key func(T) K, k int) map[K][]T {
+	if k < 0 {
+		panic("gobag: negative sample size")
+	}
+	if items == nil {
+		return nil
+	}
+
+	type stratum struct {
+		seen      int
+		reservoir []sampled[T]
+	}
+	strata := make(map[K]*stratum)
+	for i, item := range items {
+		group := key(item)
+		s, ok := strata[group]
+		if !ok {
+			s = &stratum{}
+			strata[group] = s
+		}
+		if s.seen < k {
+			s.reservoir = append(s.reservoir, sampled[T]{index: i, element: item})
+		} else if j := r.IntN(s.seen + 1); j < k {
+			s.reservoir[j] = sampled[T]{index: i, element: item}
+		}
+		s.seen++
+	}
+
+	samples := make(map[K][]T, len(strata))
+	for group, s := range strata {
+		samples[group] = sampledElements(s.reservoir)
+	}
+	return samples
+}
+
+// AliasSampler draws indices with probabilities proportional to fixed
+// weights in O(1) time per draw, using the alias method of Walker as refined
+// by Vose.
+type AliasSampler struct {
+	probability []float64
+	alias       []int
+}
+
+// NewAliasSampler creates an AliasSampler drawing each index i with a
+// probability proportional to weights[i], in O(n) time. It returns
+// ErrInvalidWeights if a weight is negative or not finite, or if they are all
+// zero.
+func NewAliasSampler(weights []float64) (*AliasSampler, error) {
+	total, err := validateWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(weights)
+	a := &AliasSampler{probability: make([]float64, n), alias: make([]int, n)}
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	// Pair each underfull index with an overfull one topping it up.
+	for len(small) > 0 && len(large) > 0 {
+		l, g := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		a.probability[l], a.alias[l] = scaled[l], g
+		scaled[g] += scaled[l] - 1
+		if scaled[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	// What remains is full up to rounding errors.
+	for _, i := range slices.Concat(small, large) {
+		a.probability[i], a.alias[i] = 1, i
+	}
+	return a, nil
+}
+
+// Len returns the number of weights of the sampler.
+func (a *AliasSampler) Len() int {
+	return len(a.probability)
+}
+
+// Sample draws an index.
+func (a *AliasSampler) Sample(r *rand.Rand) int {
+	i := r.IntN(len(a.probability))
+	if r.Float64() < a.probability[i] {
+		return i
+	}
+	return a.alias[i]
+}
+
+// validateWeights returns the sum of weights, or ErrInvalidWeights if a
+// weight is negative or not finite, or if they are all zero.
+func validateWeights(weights []float64) (float64, error) {
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return 0, ErrInvalidWeights
+		}
+		total += w
+	}
+	if total == 0 || math.IsInf(total, 0) {
+		return 0, ErrInvalidWeights
+	}
+	return total, nil
+}
+
+// sampledElements returns the elements of samples in input order.
+func sampledElements[T any](samples []sampled[T]) []T {
+	slices.SortFunc(samples, func(a, b sampled[T]) int {
+		return cmp.Compare(a.index, b.index)
+	})
+	elements := make([]T, len(samples))
+	for i, s := range samples {
+		elements[i] = s.element
+	}
+	return elements
+}
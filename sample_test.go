@@ -0,0 +1,153 @@
+package gobag
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRand() *rand.Rand {
+	return rand.New(rand.NewPCG(42, 0))
+}
+
+func TestReservoirSample(t *testing.T) {
+	t.Run("uniform and ordered", func(t *testing.T) {
+		r := newTestRand()
+		counts := make([]int, 20)
+		const rounds = 20000
+		for range rounds {
+			sample := ReservoirSample(r, slices.Values(countTo(20)), 5)
+			require.Len(t, sample, 5)
+			require.True(t, slices.IsSorted(sample))
+			for _, v := range sample {
+				counts[v]++
+			}
+		}
+		// Each element is picked with probability 5/20.
+		for _, c := range counts {
+			require.InDelta(t, rounds/4, c, rounds/4*0.05)
+		}
+	})
+
+	t.Run("short input and reproducibility", func(t *testing.T) {
+		require.Equal(t, []int{0, 1, 2}, ReservoirSample(newTestRand(), slices.Values(countTo(3)), 10))
+		require.Empty(t, ReservoirSample(newTestRand(), slices.Values(countTo(3)), 0))
+		require.Equal(t,
+			ReservoirSample(newTestRand(), slices.Values(countTo(1000)), 10),
+			ReservoirSample(newTestRand(), slices.Values(countTo(1000)), 10))
+		require.Panics(t, func() { ReservoirSample(newTestRand(), slices.Values(countTo(3)), -1) })
+	})
+}
+
+func TestShuffle(t *testing.T) {
+	s := countTo(50)
+	Shuffle(newTestRand(), s)
+	require.NotEqual(t, countTo(50), s)
+	require.ElementsMatch(t, countTo(50), s)
+
+	again := countTo(50)
+	Shuffle(newTestRand(), again)
+	require.Equal(t, s, again)
+}
+
+func TestWeightedSample(t *testing.T) {
+	t.Run("with replacement follows the weights", func(t *testing.T) {
+		items := []string{"a", "b", "c", "d"}
+		weights := []float64{1, 2, 3, 0}
+		const draws = 60000
+		sample, err := WeightedSample(newTestRand(), items, weights, draws)
+		require.NoError(t, err)
+		require.Len(t, sample, draws)
+
+		counts := make(map[string]int)
+		for _, v := range sample {
+			counts[v]++
+		}
+		require.InDelta(t, draws/6, counts["a"], draws/6*0.05)
+		require.InDelta(t, draws/3, counts["b"], draws/3*0.05)
+		require.InDelta(t, draws/2, counts["c"], draws/2*0.05)
+		require.Zero(t, counts["d"])
+	})
+
+	t.Run("without replacement", func(t *testing.T) {
+		items := []string{"a", "b", "c", "d"}
+		weights := []float64{1, 2, 3, 0}
+		r := newTestRand()
+		firsts := make(map[string]int)
+		const rounds = 30000
+		for range rounds {
+			sample, err := WeightedSampleWithoutReplacement(r, items, weights, 2)
+			require.NoError(t, err)
+			require.Len(t, sample, 2)
+			require.NotEqual(t, sample[0], sample[1])
+			firsts[sample[0]]++
+		}
+		// The first draw follows the weights.
+		require.InDelta(t, rounds/6, firsts["a"], rounds/6*0.05)
+		require.InDelta(t, rounds/2, firsts["c"], rounds/2*0.05)
+
+		sample, err := WeightedSampleWithoutReplacement(r, items, weights, 10)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"a", "b", "c"}, sample)
+	})
+
+	t.Run("invalid weights", func(t *testing.T) {
+		r := newTestRand()
+		for _, weights := range [][]float64{{1}, {0, 0}, {1, -1}, {1, math.NaN()}, {1, math.Inf(1)}, {math.MaxFloat64, math.MaxFloat64}} {
+			_, err := WeightedSample(r, []int{1, 2}, weights, 1)
+			require.ErrorIs(t, err, ErrInvalidWeights, "%v", weights)
+			_, err = WeightedSampleWithoutReplacement(r, []int{1, 2}, weights, 1)
+			require.ErrorIs(t, err, ErrInvalidWeights, "%v", weights)
+		}
+		_, err := NewAliasSampler(nil)
+		require.ErrorIs(t, err, ErrInvalidWeights)
+	})
+
+	t.Run("alias sampler", func(t *testing.T) {
+		weights := []float64{0.1, 5, 0, 2.5, 2.4}
+		sampler, err := NewAliasSampler(weights)
+		require.NoError(t, err)
+		require.Equal(t, len(weights), sampler.Len())
+
+		r := newTestRand()
+		counts := make([]int, len(weights))
+		const draws = 100000
+		for range draws {
+			counts[sampler.Sample(r)]++
+		}
+		for i, w := range weights {
+			expected := draws * w / 10
+			require.InDelta(t, expected, counts[i], 0.05*expected+50, "index %d", i)
+		}
+	})
+}
+
+func TestStratifiedSample(t *testing.T) {
+	items := countTo(100)
+	parity := func(v int) int { return v % 3 }
+	samples := StratifiedSample(newTestRand(), items, parity, 5)
+	require.Len(t, samples, 3)
+	for key, sample := range samples {
+		require.Len(t, sample, 5)
+		require.True(t, slices.IsSorted(sample))
+		for _, v := range sample {
+			require.Equal(t, key, v%3)
+		}
+	}
+	require.Equal(t, samples, StratifiedSample(newTestRand(), items, parity, 5))
+
+	small := StratifiedSample(newTestRand(), []int{1, 2, 4}, func(v int) bool { return v%2 == 0 }, 5)
+	require.Equal(t, map[bool][]int{false: {1}, true: {2, 4}}, small)
+	require.Nil(t, StratifiedSample(newTestRand(), nil, parity, 5))
+}
+
+func countTo(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
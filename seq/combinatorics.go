@@ -0,0 +1,209 @@
+package seq
+
+import (
+	"iter"
+	"slices"
+)
+
+// The generators below yield the arrangements of the elements of their input
+// slices lazily, one at a time, in lexicographic order of the positions of the
+// elements: when the input is sorted, the arrangements are therefore sorted
+// too. Elements are told apart by their position, not their value, so an
+// input holding duplicates yields duplicate arrangements. Each arrangement is
+// a newly allocated slice that the consumer may retain.
+
+// Permutations returns a sequence yielding every ordering of the elements of
+// s, n! in total for n elements.
+//
+// Example:
+//
+//	Permutations([]int{1, 2, 3}) // [1 2 3] [1 3 2] [2 1 3] [2 3 1] [3 1 2] [3 2 1]
+func Permutations[T any](s []T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		indices := make([]int, len(s))
+		for i := range indices {
+			indices[i] = i
+		}
+		for {
+			if !yield(pick(s, indices)) {
+				return
+			}
+			// Find the longest non-increasing suffix, then swap the index
+			// before it with the smallest larger index of the suffix.
+			i := len(indices) - 2
+			for i >= 0 && indices[i] > indices[i+1] {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			j := len(indices) - 1
+			for indices[j] < indices[i] {
+				j--
+			}
+			indices[i], indices[j] = indices[j], indices[i]
+			slices.Reverse(indices[i+1:])
+		}
+	}
+}
+
+// Combinations returns a sequence yielding every selection of k elements of
+// s, kept in their order in s. It yields a single empty selection if k is
+// zero, and nothing if k exceeds len(s). Combinations panics if k is
+// negative.
+//
+// Example:
+//
+//	Combinations([]string{"a", "b", "c"}, 2) // [a b] [a c] [b c]
+func Combinations[T any](s []T, k int) iter.Seq[[]T] {
+	if k < 0 {
+		panic("seq: combination size must not be negative")
+	}
+	return func(yield func([]T) bool) {
+		n := len(s)
+		if k > n {
+			return
+		}
+		indices := make([]int, k)
+		for i := range indices {
+			indices[i] = i
+		}
+		for {
+			if !yield(pick(s, indices)) {
+				return
+			}
+			i := k - 1
+			for i >= 0 && indices[i] == n-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+			}
+		}
+	}
+}
+
+// CombinationsWithReplacement returns a sequence yielding every selection of
+// k elements of s in which an element may be selected several times, kept in
+// their order in s. It yields a single empty selection if k is zero.
+// CombinationsWithReplacement panics if k is negative.
+//
+// Example:
+//
+//	CombinationsWithReplacement([]string{"a", "b"}, 2) // [a a] [a b] [b b]
+func CombinationsWithReplacement[T any](s []T, k int) iter.Seq[[]T] {
+	if k < 0 {
+		panic("seq: combination size must not be negative")
+	}
+	return func(yield func([]T) bool) {
+		n := len(s)
+		if n == 0 && k > 0 {
+			return
+		}
+		indices := make([]int, k)
+		for {
+			if !yield(pick(s, indices)) {
+				return
+			}
+			i := k - 1
+			for i >= 0 && indices[i] == n-1 {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[i]
+			}
+		}
+	}
+}
+
+// CartesianProduct returns a sequence yielding every tuple made of one element
+// of each of the given slices, the last slice varying fastest. It yields a
+// single empty tuple if no slice is given, and nothing if any slice is empty.
+//
+// Example:
+//
+//	CartesianProduct([]string{"linux", "darwin"}, []string{"amd64", "arm64"})
+//	// [linux amd64] [linux arm64] [darwin amd64] [darwin arm64]
+func CartesianProduct[T any](sets ...[]T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for _, set := range sets {
+			if len(set) == 0 {
+				return
+			}
+		}
+		indices := make([]int, len(sets))
+		for {
+			tuple := make([]T, len(sets))
+			for i, set := range sets {
+				tuple[i] = set[indices[i]]
+			}
+			if !yield(tuple) {
+				return
+			}
+			i := len(sets) - 1
+			for i >= 0 && indices[i] == len(sets[i])-1 {
+				indices[i] = 0
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+		}
+	}
+}
+
+// PowerSet returns a sequence yielding every subset of the elements of s,
+// 2^n in total for n elements, each kept in its order in s, starting with the
+// empty subset.
+//
+// Example:
+//
+//	PowerSet([]int{1, 2, 3}) // [] [1] [1 2] [1 2 3] [1 3] [2] [2 3] [3]
+func PowerSet[T any](s []T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(s)
+		indices := make([]int, 0, n)
+		for {
+			if !yield(pick(s, indices)) {
+				return
+			}
+			// Extend the subset with the next element if there is one,
+			// otherwise drop its last element and advance the new last one.
+			if len(indices) == 0 {
+				if n == 0 {
+					return
+				}
+				indices = append(indices, 0)
+				continue
+			}
+			last := indices[len(indices)-1]
+			if last+1 < n {
+				indices = append(indices, last+1)
+				continue
+			}
+			indices = indices[:len(indices)-1]
+			if len(indices) == 0 {
+				return
+			}
+			indices[len(indices)-1]++
+		}
+	}
+}
+
+// pick returns a new slice holding the elements of s at the given indices.
+func pick[T any](s []T, indices []int) []T {
+	picked := make([]T, len(indices))
+	for i, index := range indices {
+		picked[i] = s[index]
+	}
+	return picked
+}
@@ -0,0 +1,101 @@
+package seq
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireSortedUnique checks that arrangements of a sorted input come out in
+// strictly increasing lexicographic order.
+func requireSortedUnique(t *testing.T, arrangements [][]int) {
+	t.Helper()
+	for i := 1; i < len(arrangements); i++ {
+		require.Negative(t, slices.Compare(arrangements[i-1], arrangements[i]), "%v before %v", arrangements[i-1], arrangements[i])
+	}
+}
+
+func countUpTo(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+func TestPermutations(t *testing.T) {
+	require.Equal(t, [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}}, Collect(Permutations([]int{1, 2, 3})))
+	require.Equal(t, [][]int{{}}, Collect(Permutations([]int{})))
+
+	all := Collect(Permutations(countUpTo(6)))
+	require.Len(t, all, 720)
+	requireSortedUnique(t, all)
+
+	// Twenty elements have 20! permutations: only laziness makes this
+	// terminate.
+	input := countUpTo(20)
+	first := Collect(Take(Permutations(input), 2))
+	require.Equal(t, input, first[0])
+	require.Equal(t, []int{19, 18}, first[1][18:])
+
+	// Yielded slices may be retained.
+	first[0][0] = 100
+	require.Equal(t, 0, input[0])
+}
+
+func TestCombinations(t *testing.T) {
+	require.Equal(t, [][]string{{"a", "b"}, {"a", "c"}, {"b", "c"}}, Collect(Combinations([]string{"a", "b", "c"}, 2)))
+	require.Equal(t, [][]string{{}}, Collect(Combinations([]string{"a"}, 0)))
+	require.Empty(t, Collect(Combinations([]string{"a"}, 2)))
+	require.Equal(t, [][]string{{"a"}}, Collect(Combinations([]string{"a"}, 1)))
+	require.Panics(t, func() { Combinations([]int{1}, -1) })
+
+	all := Collect(Combinations(countUpTo(10), 4))
+	require.Len(t, all, 210)
+	requireSortedUnique(t, all)
+	for _, c := range all {
+		require.True(t, slices.IsSorted(c))
+	}
+
+	require.Len(t, Collect(Take(Combinations(countUpTo(60), 30), 3)), 3)
+}
+
+func TestCombinationsWithReplacement(t *testing.T) {
+	require.Equal(t, [][]string{{"a", "a"}, {"a", "b"}, {"b", "b"}}, Collect(CombinationsWithReplacement([]string{"a", "b"}, 2)))
+	require.Equal(t, [][]int{{}}, Collect(CombinationsWithReplacement([]int{}, 0)))
+	require.Empty(t, Collect(CombinationsWithReplacement([]int{}, 2)))
+	require.Equal(t, [][]int{{7, 7, 7}}, Collect(CombinationsWithReplacement([]int{7}, 3)))
+	require.Panics(t, func() { CombinationsWithReplacement([]int{1}, -1) })
+
+	// C(n+k-1, k) = C(8, 3) selections.
+	all := Collect(CombinationsWithReplacement(countUpTo(6), 3))
+	require.Len(t, all, 56)
+	requireSortedUnique(t, all)
+}
+
+func TestCartesianProduct(t *testing.T) {
+	require.Equal(t, [][]string{
+		{"linux", "amd64"}, {"linux", "arm64"},
+		{"darwin", "amd64"}, {"darwin", "arm64"},
+	}, Collect(CartesianProduct([]string{"linux", "darwin"}, []string{"amd64", "arm64"})))
+	require.Equal(t, [][]int{{}}, Collect(CartesianProduct[int]()))
+	require.Empty(t, Collect(CartesianProduct([]int{1, 2}, []int{})))
+
+	all := Collect(CartesianProduct(countUpTo(3), countUpTo(4), countUpTo(2)))
+	require.Len(t, all, 24)
+	requireSortedUnique(t, all)
+
+	require.Len(t, Collect(Take(CartesianProduct(countUpTo(100), countUpTo(100), countUpTo(100), countUpTo(100)), 5)), 5)
+}
+
+func TestPowerSet(t *testing.T) {
+	require.Equal(t, [][]int{{}, {1}, {1, 2}, {1, 2, 3}, {1, 3}, {2}, {2, 3}, {3}}, Collect(PowerSet([]int{1, 2, 3})))
+	require.Equal(t, [][]int{{}}, Collect(PowerSet([]int{})))
+
+	all := Collect(PowerSet(countUpTo(10)))
+	require.Len(t, all, 1024)
+	requireSortedUnique(t, all)
+
+	require.Len(t, Collect(Take(PowerSet(countUpTo(64)), 100)), 100)
+}
@@ -0,0 +1,241 @@
+// Package seq provides lazy operations over iter.Seq and iter.Seq2 values.
+//
+// None of the functions in this package materialize intermediate slices:
+// every element is pulled from the source sequence only when the consumer
+// asks for it, so pipelines can process arbitrarily large inputs in constant
+// memory (Chunk, Window and Distinct excepted, which hold their current chunk,
+// window or seen elements respectively). Likewise, the combinatorics
+// generators such as Permutations and PowerSet build each arrangement only
+// when it is consumed, never the whole set of arrangements.
+package seq
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/neumachen/gobag"
+)
+
+// Map returns a sequence yielding the result of fn applied to each element of
+// seq.
+func Map[T, R any](seq iter.Seq[T], fn func(T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for element := range seq {
+			if !yield(fn(element)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns a sequence yielding the elements of seq for which keep
+// reports true.
+func Filter[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for element := range seq {
+			if keep(element) && !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// FlatMap returns a sequence yielding, in order, every element of the
+// sequences returned by fn for each element of seq.
+func FlatMap[T, R any](seq iter.Seq[T], fn func(T) iter.Seq[R]) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for element := range seq {
+			for mapped := range fn(element) {
+				if !yield(mapped) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Take returns a sequence yielding at most the first n elements of seq.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for element := range seq {
+			if !yield(element) {
+				return
+			}
+			taken++
+			if taken == n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns a sequence yielding the elements of seq after the first n.
+func Skip[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		skipped := 0
+		for element := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns a sequence yielding the elements of seq up to, but not
+// including, the first element for which keep reports false.
+func TakeWhile[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for element := range seq {
+			if !keep(element) || !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns a sequence yielding consecutive, non-overlapping chunks of n
+// elements of seq. The last chunk may hold fewer than n elements. Each chunk
+// is a newly allocated slice that the consumer may retain. Chunk panics if n
+// is less than 1.
+func Chunk[T any](seq iter.Seq[T], n int) iter.Seq[[]T] {
+	if n < 1 {
+		panic("seq: chunk size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, n)
+		for element := range seq {
+			chunk = append(chunk, element)
+			if len(chunk) < n {
+				continue
+			}
+			if !yield(chunk) {
+				return
+			}
+			chunk = make([]T, 0, n)
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window returns a sequence yielding every run of n consecutive elements of
+// seq, advancing by one element at a time. Nothing is yielded if seq has fewer
+// than n elements. Each window is a newly allocated slice that the consumer
+// may retain. Window panics if n is less than 1.
+func Window[T any](seq iter.Seq[T], n int) iter.Seq[[]T] {
+	if n < 1 {
+		panic("seq: window size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		window := make([]T, 0, n)
+		for element := range seq {
+			if len(window) == n {
+				window = append(window[:0], window[1:]...)
+			}
+			window = append(window, element)
+			if len(window) < n {
+				continue
+			}
+			if !yield(slices.Clone(window)) {
+				return
+			}
+		}
+	}
+}
+
+// Zip returns a sequence yielding pairs of elements taken in lockstep from a
+// and b. It stops as soon as either sequence is exhausted.
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(b)
+		defer stop()
+		for elementA := range a {
+			elementB, ok := nextB()
+			if !ok || !yield(elementA, elementB) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns a sequence yielding each element of seq along with its
+// zero-based index.
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for element := range seq {
+			if !yield(i, element) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Concat returns a sequence yielding the elements of each of seqs in turn.
+func Concat[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, seq := range seqs {
+			for element := range seq {
+				if !yield(element) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Distinct returns a sequence yielding only the first occurrence of each
+// element of seq. It is the lazy counterpart of gobag.FilterUniqueElements and
+// yields the elements in the same order.
+func Distinct[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seenElements := gobag.NewSet[T]()
+		for element := range seq {
+			if seenElements.Has(element) {
+				continue
+			}
+			seenElements.Add(element)
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// Contains reports whether target is yielded by seq. It is the lazy
+// counterpart of gobag.SliceContains and stops consuming seq as soon as target
+// is found.
+func Contains[T comparable](seq iter.Seq[T], target T) bool {
+	for element := range seq {
+		if element == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Reduce folds the elements of seq into a single value, starting from initial
+// and applying fn to the accumulator and each element in turn.
+func Reduce[T, R any](seq iter.Seq[T], initial R, fn func(R, T) R) R {
+	accumulator := initial
+	for element := range seq {
+		accumulator = fn(accumulator, element)
+	}
+	return accumulator
+}
+
+// Collect gathers the elements of seq into a new slice. It returns nil if seq
+// yields no elements.
+func Collect[T any](seq iter.Seq[T]) []T {
+	return slices.Collect(seq)
+}
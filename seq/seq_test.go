@@ -0,0 +1,109 @@
+package seq
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/neumachen/gobag"
+	"github.com/stretchr/testify/require"
+)
+
+// naturals yields 0, 1, 2, ... forever, so any test using it proves that the
+// operation under test is lazy.
+func naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestMapFilter(t *testing.T) {
+	even := Filter(naturals(), func(i int) bool { return i%2 == 0 })
+	squares := Map(even, func(i int) int { return i * i })
+	require.Equal(t, []int{0, 4, 16, 36}, Collect(Take(squares, 4)))
+}
+
+func TestFlatMap(t *testing.T) {
+	words := slices.Values([]string{"a b", "c", ""})
+	result := FlatMap(words, func(s string) iter.Seq[string] {
+		return slices.Values(strings.Fields(s))
+	})
+	require.Equal(t, []string{"a", "b", "c"}, Collect(result))
+	require.Equal(t, []string{"a"}, Collect(Take(result, 1)))
+}
+
+func TestTakeSkip(t *testing.T) {
+	require.Nil(t, Collect(Take(naturals(), 0)))
+	require.Equal(t, []int{3, 4, 5}, Collect(Take(Skip(naturals(), 3), 3)))
+	require.Nil(t, Collect(Skip(slices.Values([]int{1, 2}), 5)))
+	require.Equal(t, []int{0, 1, 2}, Collect(TakeWhile(naturals(), func(i int) bool { return i < 3 })))
+}
+
+func TestChunk(t *testing.T) {
+	chunks := Collect(Chunk(slices.Values([]int{1, 2, 3, 4, 5}), 2))
+	require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+
+	require.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}}, Collect(Take(Chunk(naturals(), 3), 2)))
+	require.Nil(t, Collect(Chunk(slices.Values([]int{}), 2)))
+	require.Panics(t, func() { Chunk(naturals(), 0) })
+}
+
+func TestWindow(t *testing.T) {
+	windows := Collect(Window(slices.Values([]int{1, 2, 3, 4}), 3))
+	require.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}}, windows)
+
+	require.Nil(t, Collect(Window(slices.Values([]int{1, 2}), 3)))
+	require.Equal(t, [][]int{{0, 1}, {1, 2}}, Collect(Take(Window(naturals(), 2), 2)))
+	require.Panics(t, func() { Window(naturals(), 0) })
+}
+
+func TestZipEnumerate(t *testing.T) {
+	zipped := maps.Collect(Zip(slices.Values([]string{"a", "b", "c"}), naturals()))
+	require.Equal(t, map[string]int{"a": 0, "b": 1, "c": 2}, zipped)
+
+	var indexes []int
+	var values []string
+	for i, v := range Enumerate(slices.Values([]string{"x", "y"})) {
+		indexes = append(indexes, i)
+		values = append(values, v)
+	}
+	require.Equal(t, []int{0, 1}, indexes)
+	require.Equal(t, []string{"x", "y"}, values)
+}
+
+func TestConcat(t *testing.T) {
+	result := Concat(slices.Values([]int{1, 2}), slices.Values([]int{}), slices.Values([]int{3}))
+	require.Equal(t, []int{1, 2, 3}, Collect(result))
+	require.Equal(t, []int{1, 2, 3, 0, 1}, Collect(Take(Concat(result, naturals()), 5)))
+}
+
+func TestDistinct(t *testing.T) {
+	input := []string{"apple", "banana", "banana", "cherry", "apple"}
+	require.Equal(t, gobag.FilterUniqueElements(input), Collect(Distinct(slices.Values(input))))
+
+	modulo := Map(naturals(), func(i int) int { return i % 3 })
+	require.Equal(t, []int{0, 1, 2}, Collect(Take(Distinct(modulo), 3)))
+}
+
+func TestContains(t *testing.T) {
+	input := []int{1, 2, 3}
+	require.Equal(t, gobag.SliceContains(input, 2), Contains(slices.Values(input), 2))
+	require.Equal(t, gobag.SliceContains(input, 9), Contains(slices.Values(input), 9))
+	require.True(t, Contains(naturals(), 1000))
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce(Take(naturals(), 5), 0, func(acc, i int) int { return acc + i })
+	require.Equal(t, 10, sum)
+
+	joined := Reduce(slices.Values([]int{1, 2, 3}), "", func(acc string, i int) string {
+		return acc + string(rune('0'+i))
+	})
+	require.Equal(t, "123", joined)
+}
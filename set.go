@@ -0,0 +1,245 @@
+package gobag
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// Set is an unordered collection of unique elements. The elements must be of
+// a comparable type, denoted by the type parameter T.
+//
+// The zero value of Set is an empty set ready to use. A Set must not be copied
+// after first use, and it is not safe for concurrent use without external
+// synchronization.
+type Set[T comparable] struct {
+	elements map[T]struct{}
+}
+
+// NewSet creates a new Set containing the given elements. Duplicate elements
+// are only stored once. It returns a pointer to the created Set.
+func NewSet[T comparable](elements ...T) *Set[T] {
+	s := &Set[T]{elements: make(map[T]struct{}, len(elements))}
+	for _, element := range elements {
+		s.elements[element] = struct{}{}
+	}
+	return s
+}
+
+// SetFromSeq creates a new Set from the elements yielded by seq.
+func SetFromSeq[T comparable](seq iter.Seq[T]) *Set[T] {
+	s := NewSet[T]()
+	for element := range seq {
+		s.elements[element] = struct{}{}
+	}
+	return s
+}
+
+// Add inserts the given elements into the set. Elements that are already
+// present are ignored.
+func (s *Set[T]) Add(elements ...T) {
+	if s.elements == nil {
+		s.elements = make(map[T]struct{}, len(elements))
+	}
+	for _, element := range elements {
+		s.elements[element] = struct{}{}
+	}
+}
+
+// Remove deletes the given elements from the set. Elements that are not
+// present are ignored.
+func (s *Set[T]) Remove(elements ...T) {
+	for _, element := range elements {
+		delete(s.elements, element)
+	}
+}
+
+// Has reports whether element is present in the set.
+func (s *Set[T]) Has(element T) bool {
+	_, ok := s.elements[element]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (s *Set[T]) Len() int {
+	return len(s.elements)
+}
+
+// Clear removes all elements from the set.
+func (s *Set[T]) Clear() {
+	clear(s.elements)
+}
+
+// Clone returns a copy of the set.
+func (s *Set[T]) Clone() *Set[T] {
+	c := &Set[T]{elements: make(map[T]struct{}, len(s.elements))}
+	for element := range s.elements {
+		c.elements[element] = struct{}{}
+	}
+	return c
+}
+
+// All returns an iterator over the elements of the set. The iteration order
+// is not specified and is not guaranteed to be the same from one call to the
+// next.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for element := range s.elements {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// Slice returns the elements of the set as a slice in unspecified order. It
+// returns nil if the set is empty.
+func (s *Set[T]) Slice() []T {
+	if len(s.elements) == 0 {
+		return nil
+	}
+	result := make([]T, 0, len(s.elements))
+	for element := range s.elements {
+		result = append(result, element)
+	}
+	return result
+}
+
+// Union returns a new set containing the elements present in s, other or
+// both.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := s.Clone()
+	for element := range other.elements {
+		result.elements[element] = struct{}{}
+	}
+	return result
+}
+
+// Intersect returns a new set containing the elements present in both s and
+// other.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	smaller, larger := s, other
+	if smaller.Len() > larger.Len() {
+		smaller, larger = larger, smaller
+	}
+	result := NewSet[T]()
+	for element := range smaller.elements {
+		if larger.Has(element) {
+			result.elements[element] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference returns a new set containing the elements present in s but not
+// in other.
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for element := range s.elements {
+		if !other.Has(element) {
+			result.elements[element] = struct{}{}
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns a new set containing the elements present in
+// exactly one of s and other.
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := s.Difference(other)
+	for element := range other.elements {
+		if !s.Has(element) {
+			result.elements[element] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IsSubset reports whether every element of s is also present in other.
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for element := range s.elements {
+		if !other.Has(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether every element of other is also present in s.
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal reports whether s and other contain exactly the same elements.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// MarshalJSON encodes the set as a JSON array. The elements are sorted so
+// that equal sets always produce identical output: numbers and strings are
+// sorted by value, any other type is sorted by its JSON encoding.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	elements := s.Slice()
+	if elements == nil {
+		return []byte(`[]`), nil
+	}
+	if sortOrderedElements(elements) {
+		return json.Marshal(elements)
+	}
+
+	encoded := make([]json.RawMessage, len(elements))
+	for i := range elements {
+		b, err := json.Marshal(elements[i])
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+	slices.SortFunc(encoded, func(a, b json.RawMessage) int {
+		return bytes.Compare(a, b)
+	})
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a JSON array into the set, replacing its current
+// contents. Duplicate array entries are only stored once.
+func (s *Set[T]) UnmarshalJSON(b []byte) error {
+	var elements []T
+	if err := json.Unmarshal(b, &elements); err != nil {
+		return err
+	}
+	s.elements = make(map[T]struct{}, len(elements))
+	for _, element := range elements {
+		s.elements[element] = struct{}{}
+	}
+	return nil
+}
+
+// sortOrderedElements sorts elements in place when their underlying kind is
+// a number or a string. It returns false, leaving elements untouched, for any
+// other kind.
+func sortOrderedElements[T comparable](elements []T) bool {
+	var compare func(a, b reflect.Value) int
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Int(), b.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Uint(), b.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Float(), b.Float()) }
+	case reflect.String:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) }
+	default:
+		return false
+	}
+	slices.SortFunc(elements, func(a, b T) int {
+		return compare(reflect.ValueOf(a), reflect.ValueOf(b))
+	})
+	return true
+}
@@ -0,0 +1,117 @@
+package gobag
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("add, remove and has", func(t *testing.T) {
+		var s Set[string]
+		require.Equal(t, 0, s.Len())
+		require.False(t, s.Has("a"))
+
+		s.Add("a", "b", "a")
+		require.Equal(t, 2, s.Len())
+		require.True(t, s.Has("a"))
+		require.True(t, s.Has("b"))
+
+		s.Remove("a", "z")
+		require.Equal(t, 1, s.Len())
+		require.False(t, s.Has("a"))
+
+		s.Clear()
+		require.Equal(t, 0, s.Len())
+		require.Nil(t, s.Slice())
+	})
+
+	t.Run("algebra", func(t *testing.T) {
+		a := NewSet(1, 2, 3, 4)
+		b := NewSet(3, 4, 5)
+
+		require.True(t, a.Union(b).Equal(NewSet(1, 2, 3, 4, 5)))
+		require.True(t, a.Intersect(b).Equal(NewSet(3, 4)))
+		require.True(t, a.Difference(b).Equal(NewSet(1, 2)))
+		require.True(t, b.Difference(a).Equal(NewSet(5)))
+		require.True(t, a.SymmetricDifference(b).Equal(NewSet(1, 2, 5)))
+
+		// the operands must be left untouched
+		require.Equal(t, 4, a.Len())
+		require.Equal(t, 3, b.Len())
+	})
+
+	t.Run("subset and superset", func(t *testing.T) {
+		a := NewSet("a", "b")
+		b := NewSet("a", "b", "c")
+
+		require.True(t, a.IsSubset(b))
+		require.False(t, b.IsSubset(a))
+		require.True(t, b.IsSuperset(a))
+		require.False(t, a.IsSuperset(b))
+		require.True(t, NewSet[string]().IsSubset(a))
+		require.False(t, a.Equal(b))
+		require.True(t, a.Equal(a.Clone()))
+	})
+
+	t.Run("iteration", func(t *testing.T) {
+		s := NewSet(3, 1, 2)
+		result := slices.Sorted(s.All())
+		require.Equal(t, []int{1, 2, 3}, result)
+
+		count := 0
+		for range s.All() {
+			count++
+			break
+		}
+		require.Equal(t, 1, count)
+
+		require.True(t, SetFromSeq(slices.Values([]int{1, 1, 2})).Equal(NewSet(1, 2)))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(NewSet(10, 9, 1))
+		require.NoError(t, err)
+		require.Equal(t, `[1,9,10]`, string(b))
+
+		b, err = json.Marshal(NewSet("b", "c", "a"))
+		require.NoError(t, err)
+		require.Equal(t, `["a","b","c"]`, string(b))
+
+		b, err = json.Marshal(NewSet[int]())
+		require.NoError(t, err)
+		require.Equal(t, `[]`, string(b))
+
+		type point struct{ X, Y int }
+		b, err = json.Marshal(NewSet(point{2, 1}, point{1, 2}))
+		require.NoError(t, err)
+		require.Equal(t, `[{"X":1,"Y":2},{"X":2,"Y":1}]`, string(b))
+
+		var s Set[string]
+		require.NoError(t, json.Unmarshal([]byte(`["x","y","x"]`), &s))
+		require.True(t, s.Equal(NewSet("x", "y")))
+
+		require.Error(t, json.Unmarshal([]byte(`{"x":1}`), &s))
+	})
+}
+
+func TestSet_MatchesSliceFunctions(t *testing.T) {
+	source := []string{"a", "b", "c", "c"}
+	reference := []string{"b", "c", "d"}
+
+	left, right := SliceExclusion(source, reference)
+	require.True(t, NewSet(left...).Equal(NewSet(source...).Difference(NewSet(reference...))))
+	require.True(t, NewSet(right...).Equal(NewSet(reference...).Difference(NewSet(source...))))
+
+	intersection := SliceIntersection(source, reference)
+	require.True(t, NewSet(intersection...).Equal(NewSet(source...).Intersect(NewSet(reference...))))
+
+	require.ElementsMatch(t, FilterUniqueElements(source), NewSet(source...).Slice())
+
+	left, right = SliceExclusion([]string{"a"}, []string{"a"})
+	require.Nil(t, left)
+	require.Nil(t, right)
+	require.Nil(t, SliceIntersection([]string{"a"}, []string{"b"}))
+}
@@ -0,0 +1,193 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+var bloomMagic = [4]byte{'G', 'B', 'B', 'F'}
+
+const bloomHeaderSize = 4 + 1 + 8 + 4 + 8
+
+// BloomFilter is a space-efficient probabilistic set. It never reports a
+// false negative: Has always returns true for an added element. It may report
+// a false positive with a probability bounded by the rate it was sized for,
+// as long as no more than the expected number of elements are added.
+//
+// A BloomFilter is not safe for concurrent use without external
+// synchronization.
+type BloomFilter[T any] struct {
+	words  []uint64
+	m      uint64
+	k      uint32
+	count  uint64
+	hasher Hasher[T]
+}
+
+// NewBloomFilter creates a BloomFilter sized to hold expectedElements with a
+// false positive rate of at most falsePositiveRate, hashing elements with
+// hasher. It returns ErrInvalidParameter if expectedElements is zero or
+// falsePositiveRate is not in the open interval (0, 1).
+func NewBloomFilter[T any](expectedElements uint64, falsePositiveRate float64, hasher Hasher[T]) (*BloomFilter[T], error) {
+	if expectedElements == 0 {
+		return nil, fmt.Errorf("%w: expected elements must be positive", ErrInvalidParameter)
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		return nil, fmt.Errorf("%w: false positive rate %v not in (0, 1)", ErrInvalidParameter, falsePositiveRate)
+	}
+
+	n := float64(expectedElements)
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := max(math.Round(m/n*math.Ln2), 1)
+	return newBloomFilter(uint64(m), uint32(k), hasher), nil
+}
+
+func newBloomFilter[T any](m uint64, k uint32, hasher Hasher[T]) *BloomFilter[T] {
+	return &BloomFilter[T]{
+		words:  make([]uint64, bloomWords(m)),
+		m:      m,
+		k:      k,
+		hasher: hasher,
+	}
+}
+
+// bloomWords returns the number of words holding m bits, without overflowing
+// for m close to math.MaxUint64.
+func bloomWords(m uint64) uint64 {
+	words := m / 64
+	if m%64 != 0 {
+		words++
+	}
+	return words
+}
+
+// DecodeBloomFilter decodes a BloomFilter encoded with MarshalBinary. The
+// hasher must be the one the filter was built with.
+func DecodeBloomFilter[T any](data []byte, hasher Hasher[T]) (*BloomFilter[T], error) {
+	f := &BloomFilter[T]{hasher: hasher}
+	if err := f.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Add inserts element into the filter.
+func (f *BloomFilter[T]) Add(element T) {
+	h1, h2 := splitHash(f.hasher(element))
+	for i := range uint64(f.k) {
+		position := (h1 + i*h2) % f.m
+		f.words[position/64] |= 1 << (position % 64)
+	}
+	f.count++
+}
+
+// Has reports whether element may have been added to the filter. A false
+// result is definitive, a true result is correct with a probability of about
+// 1-FalsePositiveRate().
+func (f *BloomFilter[T]) Has(element T) bool {
+	h1, h2 := splitHash(f.hasher(element))
+	for i := range uint64(f.k) {
+		position := (h1 + i*h2) % f.m
+		if f.words[position/64]&(1<<(position%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestAndAdd inserts element into the filter and reports whether it may have
+// been added before.
+func (f *BloomFilter[T]) TestAndAdd(element T) bool {
+	h1, h2 := splitHash(f.hasher(element))
+	present := true
+	for i := range uint64(f.k) {
+		position := (h1 + i*h2) % f.m
+		mask := uint64(1) << (position % 64)
+		if f.words[position/64]&mask == 0 {
+			present = false
+			f.words[position/64] |= mask
+		}
+	}
+	f.count++
+	return present
+}
+
+// Count returns the number of Add and TestAndAdd calls recorded by the
+// filter, including those of filters merged into it. Duplicates are counted
+// every time.
+func (f *BloomFilter[T]) Count() uint64 {
+	return f.count
+}
+
+// Bits returns the number of bits of the filter.
+func (f *BloomFilter[T]) Bits() uint64 {
+	return f.m
+}
+
+// HashFunctions returns the number of bit positions set per element.
+func (f *BloomFilter[T]) HashFunctions() uint32 {
+	return f.k
+}
+
+// FalsePositiveRate estimates the current false positive rate of the filter
+// from the fraction of bits set.
+func (f *BloomFilter[T]) FalsePositiveRate() float64 {
+	set := 0
+	for _, word := range f.words {
+		set += bits.OnesCount64(word)
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// Union merges other into f, so that f reports every element added to either
+// filter. It returns ErrIncompatible if the filters do not have the same
+// number of bits and hash functions.
+func (f *BloomFilter[T]) Union(other *BloomFilter[T]) error {
+	if f.m != other.m || f.k != other.k {
+		return fmt.Errorf("%w: bloom filters of %d bits/%d hashes and %d bits/%d hashes",
+			ErrIncompatible, f.m, f.k, other.m, other.k)
+	}
+	for i := range f.words {
+		f.words[i] |= other.words[i]
+	}
+	f.count += other.count
+	return nil
+}
+
+// MarshalBinary encodes the filter. The hasher is not part of the encoding.
+func (f *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, bloomHeaderSize, bloomHeaderSize+8*len(f.words))
+	copy(data, bloomMagic[:])
+	data[4] = 1 // version
+	binary.LittleEndian.PutUint64(data[5:], f.m)
+	binary.LittleEndian.PutUint32(data[13:], f.k)
+	binary.LittleEndian.PutUint64(data[17:], f.count)
+	for _, word := range f.words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter encoded with MarshalBinary, replacing the
+// contents of f but keeping its hasher.
+func (f *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < bloomHeaderSize || [4]byte(data[:4]) != bloomMagic || data[4] != 1 {
+		return fmt.Errorf("%w: not a bloom filter", ErrInvalidEncoding)
+	}
+	m := binary.LittleEndian.Uint64(data[5:])
+	k := binary.LittleEndian.Uint32(data[13:])
+	count := binary.LittleEndian.Uint64(data[17:])
+	payload := data[bloomHeaderSize:]
+	if m == 0 || k == 0 || uint64(len(payload)) != bloomWords(m)*8 {
+		return fmt.Errorf("%w: bloom filter of %d bits with %d bytes of payload", ErrInvalidEncoding, m, len(payload))
+	}
+
+	f.words = make([]uint64, len(payload)/8)
+	for i := range f.words {
+		f.words[i] = binary.LittleEndian.Uint64(payload[8*i:])
+	}
+	f.m, f.k, f.count = m, k, count
+	return nil
+}
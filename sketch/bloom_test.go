@@ -0,0 +1,104 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilter(t *testing.T) {
+	t.Run("no false negatives and bounded false positives", func(t *testing.T) {
+		const n = 10000
+		filter, err := NewBloomFilter[int](n, 0.01, HashInteger[int])
+		require.NoError(t, err)
+
+		for i := range n {
+			filter.Add(i)
+		}
+		for i := range n {
+			require.True(t, filter.Has(i))
+		}
+
+		falsePositives := 0
+		for i := n; i < 2*n; i++ {
+			if filter.Has(i) {
+				falsePositives++
+			}
+		}
+		require.Less(t, float64(falsePositives)/n, 0.02)
+		require.InDelta(t, 0.01, filter.FalsePositiveRate(), 0.005)
+		require.Equal(t, uint64(n), filter.Count())
+	})
+
+	t.Run("sizing", func(t *testing.T) {
+		filter, err := NewBloomFilter[string](1000, 0.01, HashString)
+		require.NoError(t, err)
+		require.Equal(t, uint64(9586), filter.Bits())
+		require.Equal(t, uint32(7), filter.HashFunctions())
+
+		_, err = NewBloomFilter[string](0, 0.01, HashString)
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		_, err = NewBloomFilter[string](10, 1, HashString)
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		_, err = NewBloomFilter[string](10, 0, HashString)
+		require.ErrorIs(t, err, ErrInvalidParameter)
+	})
+
+	t.Run("test and add", func(t *testing.T) {
+		filter, err := NewBloomFilter[string](100, 0.001, HashString)
+		require.NoError(t, err)
+		require.False(t, filter.TestAndAdd("a"))
+		require.True(t, filter.TestAndAdd("a"))
+		require.True(t, filter.Has("a"))
+		require.False(t, filter.Has("b"))
+	})
+
+	t.Run("union", func(t *testing.T) {
+		a, _ := NewBloomFilter[string](100, 0.01, HashString)
+		b, _ := NewBloomFilter[string](100, 0.01, HashString)
+		a.Add("left")
+		b.Add("right")
+
+		require.NoError(t, a.Union(b))
+		require.True(t, a.Has("left"))
+		require.True(t, a.Has("right"))
+		require.Equal(t, uint64(2), a.Count())
+
+		c, _ := NewBloomFilter[string](1000, 0.01, HashString)
+		require.ErrorIs(t, a.Union(c), ErrIncompatible)
+	})
+
+	t.Run("binary round trip", func(t *testing.T) {
+		filter, _ := NewBloomFilter[string](500, 0.01, HashString)
+		for i := range 500 {
+			filter.Add(strconv.Itoa(i))
+		}
+
+		data, err := filter.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := DecodeBloomFilter(data, HashString)
+		require.NoError(t, err)
+		require.Equal(t, filter.Bits(), decoded.Bits())
+		require.Equal(t, filter.HashFunctions(), decoded.HashFunctions())
+		require.Equal(t, filter.Count(), decoded.Count())
+		for i := range 500 {
+			require.True(t, decoded.Has(strconv.Itoa(i)))
+		}
+
+		_, err = DecodeBloomFilter(data[:len(data)-1], HashString)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+		_, err = DecodeBloomFilter([]byte("nope"), HashString)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+
+		// A header claiming 2^64-1 bits must not be accepted with an empty
+		// payload.
+		crafted := slices.Clone(data[:bloomHeaderSize])
+		binary.LittleEndian.PutUint64(crafted[5:], math.MaxUint64)
+		_, err = DecodeBloomFilter(crafted, HashString)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+	})
+}
@@ -0,0 +1,172 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+var countMinMagic = [4]byte{'G', 'B', 'C', 'M'}
+
+const countMinHeaderSize = 4 + 1 + 4 + 4 + 8
+
+// CountMinSketch estimates the frequency of elements in a stream. Estimates
+// never undercount: Estimate returns at least the true count of an element,
+// and with probability 1-delta overcounts by at most epsilon times the total
+// count of the stream, where epsilon and delta are the sizing parameters.
+//
+// A CountMinSketch is not safe for concurrent use without external
+// synchronization.
+type CountMinSketch[T any] struct {
+	counters []uint64
+	width    uint32
+	depth    uint32
+	total    uint64
+	hasher   Hasher[T]
+}
+
+// NewCountMinSketch creates a CountMinSketch whose estimates are within
+// epsilon times the total count of the true count with probability 1-delta,
+// hashing elements with hasher. It returns ErrInvalidParameter if epsilon or
+// delta is not in the open interval (0, 1), or if they call for a table of
+// counters too large to allocate.
+func NewCountMinSketch[T any](epsilon, delta float64, hasher Hasher[T]) (*CountMinSketch[T], error) {
+	if !(epsilon > 0 && epsilon < 1) {
+		return nil, fmt.Errorf("%w: epsilon %v not in (0, 1)", ErrInvalidParameter, epsilon)
+	}
+	if !(delta > 0 && delta < 1) {
+		return nil, fmt.Errorf("%w: delta %v not in (0, 1)", ErrInvalidParameter, delta)
+	}
+
+	// Size the table in float64, which cannot wrap around, before converting
+	// to integers.
+	width := math.Ceil(math.E / epsilon)
+	depth := math.Ceil(math.Log(1 / delta))
+	if width > math.MaxUint32 || depth > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: %vx%v counters exceed the maximum dimensions", ErrInvalidParameter, depth, width)
+	}
+	if width*depth > math.MaxInt/8 {
+		return nil, fmt.Errorf("%w: %vx%v counters cannot be allocated", ErrInvalidParameter, depth, width)
+	}
+	return &CountMinSketch[T]{
+		counters: make([]uint64, uint64(width)*uint64(depth)),
+		width:    uint32(width),
+		depth:    uint32(depth),
+		hasher:   hasher,
+	}, nil
+}
+
+// DecodeCountMinSketch decodes a CountMinSketch encoded with MarshalBinary.
+// The hasher must be the one the sketch was built with.
+func DecodeCountMinSketch[T any](data []byte, hasher Hasher[T]) (*CountMinSketch[T], error) {
+	s := &CountMinSketch[T]{hasher: hasher}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add records count occurrences of element.
+func (s *CountMinSketch[T]) Add(element T, count uint64) {
+	h1, h2 := splitHash(s.hasher(element))
+	for row := range uint64(s.depth) {
+		s.counters[s.index(row, h1, h2)] += count
+	}
+	s.total += count
+}
+
+// Estimate returns the estimated number of occurrences of element.
+func (s *CountMinSketch[T]) Estimate(element T) uint64 {
+	h1, h2 := splitHash(s.hasher(element))
+	estimate := uint64(math.MaxUint64)
+	for row := range uint64(s.depth) {
+		estimate = min(estimate, s.counters[s.index(row, h1, h2)])
+	}
+	return estimate
+}
+
+// TestAndAdd records one occurrence of element and reports whether it had
+// probably occurred before.
+func (s *CountMinSketch[T]) TestAndAdd(element T) bool {
+	h1, h2 := splitHash(s.hasher(element))
+	seen := true
+	for row := range uint64(s.depth) {
+		i := s.index(row, h1, h2)
+		if s.counters[i] == 0 {
+			seen = false
+		}
+		s.counters[i]++
+	}
+	s.total++
+	return seen
+}
+
+// Total returns the sum of all counts recorded by the sketch.
+func (s *CountMinSketch[T]) Total() uint64 {
+	return s.total
+}
+
+// Width returns the number of counters per row.
+func (s *CountMinSketch[T]) Width() uint32 {
+	return s.width
+}
+
+// Depth returns the number of rows, one per hash function.
+func (s *CountMinSketch[T]) Depth() uint32 {
+	return s.depth
+}
+
+// Merge adds the counts of other to s. It returns ErrIncompatible if the
+// sketches do not have the same width and depth.
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return fmt.Errorf("%w: count-min sketches of %dx%d and %dx%d counters",
+			ErrIncompatible, s.depth, s.width, other.depth, other.width)
+	}
+	for i := range s.counters {
+		s.counters[i] += other.counters[i]
+	}
+	s.total += other.total
+	return nil
+}
+
+// MarshalBinary encodes the sketch. The hasher is not part of the encoding.
+func (s *CountMinSketch[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, countMinHeaderSize, countMinHeaderSize+8*len(s.counters))
+	copy(data, countMinMagic[:])
+	data[4] = 1 // version
+	binary.LittleEndian.PutUint32(data[5:], s.width)
+	binary.LittleEndian.PutUint32(data[9:], s.depth)
+	binary.LittleEndian.PutUint64(data[13:], s.total)
+	for _, counter := range s.counters {
+		data = binary.LittleEndian.AppendUint64(data, counter)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch encoded with MarshalBinary, replacing the
+// contents of s but keeping its hasher.
+func (s *CountMinSketch[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < countMinHeaderSize || [4]byte(data[:4]) != countMinMagic || data[4] != 1 {
+		return fmt.Errorf("%w: not a count-min sketch", ErrInvalidEncoding)
+	}
+	width := binary.LittleEndian.Uint32(data[5:])
+	depth := binary.LittleEndian.Uint32(data[9:])
+	total := binary.LittleEndian.Uint64(data[13:])
+	payload := data[countMinHeaderSize:]
+	if width == 0 || depth == 0 || uint64(len(payload)) != uint64(width)*uint64(depth)*8 {
+		return fmt.Errorf("%w: count-min sketch of %dx%d counters with %d bytes of payload",
+			ErrInvalidEncoding, depth, width, len(payload))
+	}
+
+	s.counters = make([]uint64, len(payload)/8)
+	for i := range s.counters {
+		s.counters[i] = binary.LittleEndian.Uint64(payload[8*i:])
+	}
+	s.width, s.depth, s.total = width, depth, total
+	return nil
+}
+
+func (s *CountMinSketch[T]) index(row, h1, h2 uint64) uint64 {
+	return row*uint64(s.width) + (h1+row*h2)%uint64(s.width)
+}
@@ -0,0 +1,88 @@
+package sketch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	t.Run("estimates within bounds", func(t *testing.T) {
+		const epsilon = 0.001
+		s, err := NewCountMinSketch[int](epsilon, 0.01, HashInteger[int])
+		require.NoError(t, err)
+		require.Equal(t, uint32(2719), s.Width())
+		require.Equal(t, uint32(5), s.Depth())
+
+		// element i occurs i%100+1 times
+		for i := range 5000 {
+			s.Add(i, uint64(i%100+1))
+		}
+		bound := uint64(epsilon * float64(s.Total()))
+		for i := range 5000 {
+			estimate := s.Estimate(i)
+			require.GreaterOrEqual(t, estimate, uint64(i%100+1))
+			require.LessOrEqual(t, estimate, uint64(i%100+1)+bound)
+		}
+		require.LessOrEqual(t, s.Estimate(-1), bound)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, err := NewCountMinSketch[int](0, 0.01, HashInteger[int])
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		_, err = NewCountMinSketch[int](0.01, 1, HashInteger[int])
+		require.ErrorIs(t, err, ErrInvalidParameter)
+
+		// A width beyond 2^32 counters must not wrap around to zero.
+		_, err = NewCountMinSketch[string](math.E/(1<<32), 0.5, HashString)
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		_, err = NewCountMinSketch[string](1e-300, 1e-300, HashString)
+		require.ErrorIs(t, err, ErrInvalidParameter)
+
+		// A tiny delta only takes a few hundred rows.
+		s, err := NewCountMinSketch[string](0.5, 1e-300, HashString)
+		require.NoError(t, err)
+		require.Equal(t, uint32(691), s.Depth())
+		s.Add("a", 3)
+		require.Equal(t, uint64(3), s.Estimate("a"))
+	})
+
+	t.Run("test and add", func(t *testing.T) {
+		s, _ := NewCountMinSketch[string](0.01, 0.01, HashString)
+		require.False(t, s.TestAndAdd("a"))
+		require.True(t, s.TestAndAdd("a"))
+		require.Equal(t, uint64(2), s.Estimate("a"))
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		a, _ := NewCountMinSketch[string](0.01, 0.01, HashString)
+		b, _ := NewCountMinSketch[string](0.01, 0.01, HashString)
+		a.Add("x", 3)
+		b.Add("x", 4)
+		b.Add("y", 1)
+
+		require.NoError(t, a.Merge(b))
+		require.Equal(t, uint64(7), a.Estimate("x"))
+		require.Equal(t, uint64(1), a.Estimate("y"))
+		require.Equal(t, uint64(8), a.Total())
+
+		c, _ := NewCountMinSketch[string](0.1, 0.01, HashString)
+		require.ErrorIs(t, a.Merge(c), ErrIncompatible)
+	})
+
+	t.Run("binary round trip", func(t *testing.T) {
+		s, _ := NewCountMinSketch[string](0.01, 0.01, HashString)
+		s.Add("x", 42)
+
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := DecodeCountMinSketch(data, HashString)
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), decoded.Estimate("x"))
+		require.Equal(t, s.Total(), decoded.Total())
+
+		_, err = DecodeCountMinSketch(data[:10], HashString)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+	})
+}
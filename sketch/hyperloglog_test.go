@@ -0,0 +1,126 @@
+package sketch
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireRelativeError(t *testing.T, expected int, actual uint64, tolerance float64) {
+	t.Helper()
+	relativeError := math.Abs(float64(actual)-float64(expected)) / float64(expected)
+	require.LessOrEqual(t, relativeError, tolerance, "expected about %d, got %d", expected, actual)
+}
+
+func TestHyperLogLog(t *testing.T) {
+	t.Run("accuracy across the range", func(t *testing.T) {
+		h, err := NewHyperLogLog[int](14, HashInteger[int])
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), h.Estimate())
+
+		added := 0
+		for _, checkpoint := range []int{10, 100, 1000, 10000, 100000, 1000000} {
+			for ; added < checkpoint; added++ {
+				h.Add(added)
+				h.Add(added) // duplicates must not be counted
+			}
+			requireRelativeError(t, checkpoint, h.Estimate(), 0.03)
+		}
+	})
+
+	t.Run("small cardinalities are nearly exact", func(t *testing.T) {
+		h, _ := NewHyperLogLog[string](10, HashString)
+		for i := range 200 {
+			h.Add(strconv.Itoa(i))
+		}
+		require.Equal(t, uint64(200), h.Estimate())
+	})
+
+	t.Run("invalid precision", func(t *testing.T) {
+		_, err := NewHyperLogLog[int](3, HashInteger[int])
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		_, err = NewHyperLogLog[int](19, HashInteger[int])
+		require.ErrorIs(t, err, ErrInvalidParameter)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		// workers see overlapping ranges: [0, 60000), [40000, 100000) and a
+		// small sparse sketch of [99000, 101000)
+		a, _ := NewHyperLogLog[int](14, HashInteger[int])
+		b, _ := NewHyperLogLog[int](14, HashInteger[int])
+		c, _ := NewHyperLogLog[int](14, HashInteger[int])
+		for i := range 60000 {
+			a.Add(i)
+		}
+		for i := 40000; i < 100000; i++ {
+			b.Add(i)
+		}
+		for i := 99000; i < 101000; i++ {
+			c.Add(i)
+		}
+
+		sparse, _ := NewHyperLogLog[int](14, HashInteger[int])
+		require.NoError(t, sparse.Merge(c))
+		requireRelativeError(t, 2000, sparse.Estimate(), 0.01)
+
+		require.NoError(t, a.Merge(b))
+		require.NoError(t, a.Merge(c))
+		requireRelativeError(t, 101000, a.Estimate(), 0.03)
+
+		require.NoError(t, c.Merge(b))
+		requireRelativeError(t, 61000, c.Estimate(), 0.03)
+
+		other, _ := NewHyperLogLog[int](12, HashInteger[int])
+		require.ErrorIs(t, a.Merge(other), ErrIncompatible)
+	})
+
+	t.Run("binary and json round trips", func(t *testing.T) {
+		for _, n := range []int{50, 50000} {
+			h, _ := NewHyperLogLog[int](12, HashInteger[int])
+			for i := range n {
+				h.Add(i)
+			}
+
+			data, err := h.MarshalBinary()
+			require.NoError(t, err)
+			decoded, err := DecodeHyperLogLog(data, HashInteger[int])
+			require.NoError(t, err)
+			require.Equal(t, h.Estimate(), decoded.Estimate())
+			require.Equal(t, h.Precision(), decoded.Precision())
+
+			encoded, err := json.Marshal(h)
+			require.NoError(t, err)
+			fromJSON, _ := NewHyperLogLog[int](4, HashInteger[int])
+			require.NoError(t, json.Unmarshal(encoded, fromJSON))
+			require.Equal(t, h.Estimate(), fromJSON.Estimate())
+
+			_, err = DecodeHyperLogLog(data[:len(data)-1], HashInteger[int])
+			require.ErrorIs(t, err, ErrInvalidEncoding)
+		}
+
+		var h HyperLogLog[int]
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"precision":12,"sketch":"!"}`), &h), ErrInvalidEncoding)
+	})
+}
+
+func TestEstimateDistinct(t *testing.T) {
+	words := make([]string, 0, 30000)
+	for i := range 30000 {
+		words = append(words, strconv.Itoa(i%10000))
+	}
+
+	estimate, err := EstimateDistinctSlice(words, 14, HashString)
+	require.NoError(t, err)
+	requireRelativeError(t, 10000, estimate, 0.03)
+
+	estimate, err = EstimateDistinct(slices.Values(words), 14, HashString)
+	require.NoError(t, err)
+	requireRelativeError(t, 10000, estimate, 0.03)
+
+	_, err = EstimateDistinct(slices.Values(words), 2, HashString)
+	require.ErrorIs(t, err, ErrInvalidParameter)
+}
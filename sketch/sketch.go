@@ -0,0 +1,119 @@
+// Package sketch provides probabilistic data structures that trade exactness
+// for a small, fixed memory footprint: they answer membership, frequency,
+// cardinality and quantile questions about streams far too large to hold in
+// memory with the exact helpers of gobag, such as SliceContains,
+// FilterUniqueElements or Percentile.
+//
+// Every structure but TDigest, which summarizes float64 values, hashes its
+// elements through a pluggable Hasher. The hashers provided by this package
+// are deterministic across processes, so sketches serialized by one program
+// can be decoded and merged by another one using the same hasher.
+package sketch
+
+import (
+	"errors"
+	"iter"
+	"math/bits"
+)
+
+var (
+	// ErrIncompatible is returned when merging two sketches whose
+	// parameters differ.
+	ErrIncompatible = errors.New("sketch: incompatible parameters")
+	// ErrInvalidEncoding is returned when decoding malformed data.
+	ErrInvalidEncoding = errors.New("sketch: invalid encoding")
+	// ErrInvalidParameter is returned by constructors given out of range
+	// parameters.
+	ErrInvalidParameter = errors.New("sketch: invalid parameter")
+)
+
+// Hasher maps an element to a uniformly distributed 64-bit hash.
+type Hasher[T any] func(T) uint64
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// HashBytes hashes b with 64-bit FNV-1a followed by a finalizing mix, which
+// spreads FNV's weak low bits over the whole word.
+func HashBytes(b []byte) uint64 {
+	h := uint64(fnvOffset64)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime64
+	}
+	return mix64(h)
+}
+
+// HashString hashes s like HashBytes hashes []byte(s), without allocating.
+func HashString(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return mix64(h)
+}
+
+// HashInteger hashes the integer v.
+func HashInteger[T Integer](v T) uint64 {
+	return mix64(uint64(v) + 0x9e3779b97f4a7c15)
+}
+
+// mix64 is the finalizer of the SplitMix64 generator, a bijection with good
+// avalanche behavior.
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// splitHash derives the two hashes used for Kirsch-Mitzenmacher double
+// hashing from a single 64-bit hash. The second hash is forced to be odd so
+// that it never degenerates to zero.
+func splitHash(h uint64) (uint64, uint64) {
+	return h, mix64(bits.RotateLeft64(h, 32)) | 1
+}
+
+// Deduper is implemented by sketches able to record an element and report
+// whether it had probably been seen before.
+type Deduper[T any] interface {
+	// TestAndAdd records element and reports whether it was probably
+	// already present.
+	TestAndAdd(element T) bool
+}
+
+// Dedupe returns a sequence yielding the elements of seq that d reports as
+// not seen before, recording every element in d. It is the streaming,
+// constant-memory counterpart of gobag.FilterUniqueElements: the first
+// occurrence of each element is yielded in order, and a false positive of d
+// may occasionally drop a first occurrence, but duplicates are never yielded.
+//
+// Example:
+//
+//	filter, _ := sketch.NewBloomFilter(100_000_000, 0.001, sketch.HashString)
+//	for id := range sketch.Dedupe(eventIDs, filter) {
+//		process(id)
+//	}
+func Dedupe[T any](seq iter.Seq[T], d Deduper[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for element := range seq {
+			if d.TestAndAdd(element) {
+				continue
+			}
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package sketch
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/neumachen/gobag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashers(t *testing.T) {
+	require.Equal(t, HashString("gobag"), HashBytes([]byte("gobag")))
+	require.NotEqual(t, HashString("a"), HashString("b"))
+	require.NotEqual(t, HashInteger(0), uint64(0))
+	require.Equal(t, HashInteger(int32(7)), HashInteger(uint64(7)))
+}
+
+func TestDedupe(t *testing.T) {
+	input := []string{"apple", "banana", "banana", "cherry", "apple", "date"}
+	filter, err := NewBloomFilter[string](100, 0.0001, HashString)
+	require.NoError(t, err)
+
+	result := slices.Collect(Dedupe(slices.Values(input), filter))
+	require.Equal(t, gobag.FilterUniqueElements(input), result)
+
+	s, err := NewCountMinSketch[string](0.001, 0.001, HashString)
+	require.NoError(t, err)
+	result = slices.Collect(Dedupe(slices.Values(input), s))
+	require.Equal(t, gobag.FilterUniqueElements(input), result)
+}
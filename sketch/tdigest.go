@@ -0,0 +1,291 @@
+package sketch
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var tdigestMagic = [4]byte{'G', 'B', 'T', 'D'}
+
+const (
+	tdigestHeaderSize   = 4 + 1 + 8 + 8 + 8 + 8 + 4
+	tdigestCentroidSize = 8 + 8
+	// tdigestBufferFactor sets how many values, relative to the compression,
+	// are buffered before being merged into the centroids.
+	tdigestBufferFactor = 5
+)
+
+// TDigest estimates quantiles of a stream of float64 values, such as request
+// latencies, in memory bounded by its compression parameter. It is the
+// merging t-digest of Dunning: values are summarized by centroids whose
+// weight is kept small near the extremes, so that tail quantiles like the
+// 99th percentile are estimated with a small relative error.
+//
+// Unlike the other structures of this package, a TDigest summarizes values
+// rather than hashes, so it has no Hasher.
+//
+// A TDigest is not safe for concurrent use without external synchronization.
+// Quantile modifies the digest to merge its buffered values.
+type TDigest struct {
+	compression float64
+	// centroids is sorted by mean.
+	centroids []centroid
+	buffer    []centroid
+	count     uint64
+	min, max  float64
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest creates an empty TDigest. The compression bounds the number of
+// centroids to about twice its value: 100 is a sensible default, and larger
+// values trade memory for accuracy. It returns ErrInvalidParameter if
+// compression is not in [10, 10000].
+func NewTDigest(compression float64) (*TDigest, error) {
+	if !(compression >= 10 && compression <= 10000) {
+		return nil, fmt.Errorf("%w: compression %v not in [10, 10000]", ErrInvalidParameter, compression)
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}, nil
+}
+
+// DecodeTDigest decodes a TDigest encoded with MarshalBinary.
+func DecodeTDigest(data []byte) (*TDigest, error) {
+	d := &TDigest{}
+	if err := d.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Add adds value to the digest. NaN values are ignored.
+func (d *TDigest) Add(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	d.buffer = append(d.buffer, centroid{mean: value, weight: 1})
+	d.count++
+	d.min = min(d.min, value)
+	d.max = max(d.max, value)
+	if len(d.buffer) >= tdigestBufferFactor*int(d.compression) {
+		d.compress()
+	}
+}
+
+// Count returns the number of values added to the digest.
+func (d *TDigest) Count() uint64 {
+	return d.count
+}
+
+// Min returns the smallest value added to the digest, or NaN if it is empty.
+func (d *TDigest) Min() float64 {
+	if d.count == 0 {
+		return math.NaN()
+	}
+	return d.min
+}
+
+// Max returns the largest value added to the digest, or NaN if it is empty.
+func (d *TDigest) Max() float64 {
+	if d.count == 0 {
+		return math.NaN()
+	}
+	return d.max
+}
+
+// Compression returns the compression parameter of the digest.
+func (d *TDigest) Compression() float64 {
+	return d.compression
+}
+
+// Quantile estimates the q-th quantile of the values added to the digest, for
+// q in [0, 1]: 0.5 estimates the median and 0.99 the 99th percentile. It
+// returns NaN if the digest is empty or q is out of range.
+func (d *TDigest) Quantile(q float64) float64 {
+	if d.count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+	d.compress()
+	cs := d.centroids
+	if len(cs) == 1 {
+		return cs[0].mean
+	}
+
+	// Each centroid is assumed to spread its weight evenly around its
+	// mean: interpolate between the means of the centroids surrounding the
+	// target rank, and between the extremes and the outer centroids.
+	rank := q * float64(d.count)
+	first, last := cs[0], cs[len(cs)-1]
+	if rank < first.weight/2 {
+		return d.min + rank/(first.weight/2)*(first.mean-d.min)
+	}
+	seen := first.weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		step := (cs[i].weight + cs[i+1].weight) / 2
+		if seen+step > rank {
+			return cs[i].mean + (rank-seen)/step*(cs[i+1].mean-cs[i].mean)
+		}
+		seen += step
+	}
+	return last.mean + min((rank-seen)/(last.weight/2), 1)*(d.max-last.mean)
+}
+
+// CDF estimates the fraction of the values added to the digest that are less
+// than or equal to value. It returns NaN if the digest is empty.
+func (d *TDigest) CDF(value float64) float64 {
+	if d.count == 0 || math.IsNaN(value) {
+		return math.NaN()
+	}
+	switch {
+	case value < d.min:
+		return 0
+	case value >= d.max:
+		return 1
+	}
+	d.compress()
+	cs := d.centroids
+	total := float64(d.count)
+	if len(cs) == 1 {
+		return (value - d.min) / (d.max - d.min)
+	}
+
+	first, last := cs[0], cs[len(cs)-1]
+	if value < first.mean {
+		return (value - d.min) / (first.mean - d.min) * first.weight / 2 / total
+	}
+	seen := first.weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		step := (cs[i].weight + cs[i+1].weight) / 2
+		if value < cs[i+1].mean {
+			return (seen + (value-cs[i].mean)/(cs[i+1].mean-cs[i].mean)*step) / total
+		}
+		seen += step
+	}
+	return (seen + (value-last.mean)/(d.max-last.mean)*last.weight/2) / total
+}
+
+// Merge adds the values summarized by other to d. Digests of different
+// compressions can be merged, the result keeping the compression of d.
+func (d *TDigest) Merge(other *TDigest) {
+	if other.count == 0 {
+		return
+	}
+	d.buffer = append(d.buffer, other.centroids...)
+	d.buffer = append(d.buffer, other.buffer...)
+	d.count += other.count
+	d.min = min(d.min, other.min)
+	d.max = max(d.max, other.max)
+	d.compress()
+}
+
+// MarshalBinary encodes the digest, merging its buffered values first.
+func (d *TDigest) MarshalBinary() ([]byte, error) {
+	d.compress()
+	data := make([]byte, tdigestHeaderSize, tdigestHeaderSize+tdigestCentroidSize*len(d.centroids))
+	copy(data, tdigestMagic[:])
+	data[4] = 1 // version
+	binary.LittleEndian.PutUint64(data[5:], math.Float64bits(d.compression))
+	binary.LittleEndian.PutUint64(data[13:], d.count)
+	binary.LittleEndian.PutUint64(data[21:], math.Float64bits(d.min))
+	binary.LittleEndian.PutUint64(data[29:], math.Float64bits(d.max))
+	binary.LittleEndian.PutUint32(data[37:], uint32(len(d.centroids)))
+	for _, c := range d.centroids {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(c.mean))
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(c.weight))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a digest encoded with MarshalBinary, replacing the
+// contents of d.
+func (d *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < tdigestHeaderSize || [4]byte(data[:4]) != tdigestMagic || data[4] != 1 {
+		return fmt.Errorf("%w: not a t-digest", ErrInvalidEncoding)
+	}
+	compression := math.Float64frombits(binary.LittleEndian.Uint64(data[5:]))
+	count := binary.LittleEndian.Uint64(data[13:])
+	minValue := math.Float64frombits(binary.LittleEndian.Uint64(data[21:]))
+	maxValue := math.Float64frombits(binary.LittleEndian.Uint64(data[29:]))
+	n := binary.LittleEndian.Uint32(data[37:])
+	payload := data[tdigestHeaderSize:]
+	if !(compression >= 10 && compression <= 10000) || uint64(len(payload)) != uint64(n)*tdigestCentroidSize {
+		return fmt.Errorf("%w: t-digest of compression %v with %d centroids in %d bytes", ErrInvalidEncoding, compression, n, len(payload))
+	}
+
+	centroids := make([]centroid, n)
+	total := 0.0
+	for i := range centroids {
+		c := centroid{
+			mean:   math.Float64frombits(binary.LittleEndian.Uint64(payload[16*i:])),
+			weight: math.Float64frombits(binary.LittleEndian.Uint64(payload[16*i+8:])),
+		}
+		if !(c.weight > 0) || math.IsNaN(c.mean) || c.mean < minValue || c.mean > maxValue ||
+			i > 0 && c.mean < centroids[i-1].mean {
+			return fmt.Errorf("%w: invalid t-digest centroid %d", ErrInvalidEncoding, i)
+		}
+		centroids[i] = c
+		total += c.weight
+	}
+	if total != float64(count) || count == 0 && (n != 0 || !math.IsInf(minValue, 1) || !math.IsInf(maxValue, -1)) {
+		return fmt.Errorf("%w: t-digest centroids do not match its count", ErrInvalidEncoding)
+	}
+
+	d.compression, d.count, d.min, d.max = compression, count, minValue, maxValue
+	d.centroids, d.buffer = centroids, nil
+	return nil
+}
+
+// compress merges the buffered values into the centroids. Adjacent centroids
+// are merged as long as the merged centroid spans at most one unit of the
+// scale function k(q) = compression/(2π)·asin(2q-1), whose slope grows near
+// the extremes to keep the centroids there small.
+func (d *TDigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	all := append(d.buffer, d.centroids...)
+	slices.SortFunc(all, func(a, b centroid) int {
+		return cmp.Compare(a.mean, b.mean)
+	})
+
+	total := float64(d.count)
+	merged := make([]centroid, 0, 2*int(d.compression))
+	current := all[0]
+	seen := 0.0
+	limit := d.quantileLimit(0)
+	for _, c := range all[1:] {
+		if (seen+current.weight+c.weight)/total <= limit {
+			current.weight += c.weight
+			current.mean += (c.mean - current.mean) * c.weight / current.weight
+			continue
+		}
+		merged = append(merged, current)
+		seen += current.weight
+		limit = d.quantileLimit(seen / total)
+		current = c
+	}
+	merged = append(merged, current)
+
+	// Keep the storage of the buffer for the next values.
+	clear(d.buffer)
+	d.centroids, d.buffer = merged, d.buffer[:0]
+}
+
+// quantileLimit returns the largest quantile a centroid starting at quantile
+// q may reach.
+func (d *TDigest) quantileLimit(q float64) float64 {
+	k := d.compression/(2*math.Pi)*math.Asin(2*q-1) + 1
+	if k >= d.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/d.compression) + 1) / 2
+}
@@ -0,0 +1,131 @@
+package sketch
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireQuantiles checks the estimates of d against the exact quantiles of
+// the sorted values, allowing a rank error of tolerance(q).
+func requireQuantiles(t *testing.T, d *TDigest, sorted []float64) {
+	t.Helper()
+	for _, q := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999} {
+		estimate := d.Quantile(q)
+		rank, _ := slices.BinarySearch(sorted, estimate)
+		rankError := math.Abs(float64(rank)/float64(len(sorted)) - q)
+		// The error shrinks towards the tails, like q(1-q).
+		require.LessOrEqual(t, rankError, 0.002+0.03*q*(1-q), "quantile %v estimated as %v", q, estimate)
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	distributions := map[string]func(r *rand.Rand) float64{
+		"uniform":     func(r *rand.Rand) float64 { return r.Float64() },
+		"normal":      func(r *rand.Rand) float64 { return r.NormFloat64()*10 + 100 },
+		"exponential": func(r *rand.Rand) float64 { return r.ExpFloat64() * 250 },
+	}
+	for name, draw := range distributions {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, 2))
+			d, err := NewTDigest(100)
+			require.NoError(t, err)
+			values := make([]float64, 100000)
+			for i := range values {
+				values[i] = draw(r)
+				d.Add(values[i])
+			}
+			slices.Sort(values)
+
+			require.Equal(t, uint64(len(values)), d.Count())
+			require.Equal(t, values[0], d.Min())
+			require.Equal(t, values[len(values)-1], d.Max())
+			require.Equal(t, values[0], d.Quantile(0))
+			require.Equal(t, values[len(values)-1], d.Quantile(1))
+			require.LessOrEqual(t, len(d.centroids), 2*int(d.Compression()))
+			requireQuantiles(t, d, values)
+
+			for _, q := range []float64{0.1, 0.5, 0.99} {
+				require.InDelta(t, q, d.CDF(d.Quantile(q)), 0.01)
+			}
+			require.Zero(t, d.CDF(values[0]-1))
+			require.Equal(t, 1.0, d.CDF(values[len(values)-1]))
+		})
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(3, 4))
+		var values []float64
+		merged, _ := NewTDigest(100)
+		for range 8 {
+			part, _ := NewTDigest(100)
+			for range 20000 {
+				v := r.ExpFloat64()
+				part.Add(v)
+				values = append(values, v)
+			}
+			merged.Merge(part)
+		}
+		slices.Sort(values)
+		require.Equal(t, uint64(len(values)), merged.Count())
+		requireQuantiles(t, merged, values)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		d, _ := NewTDigest(50)
+		for i := range 1000 {
+			d.Add(float64(i))
+		}
+		data, err := d.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := DecodeTDigest(data)
+		require.NoError(t, err)
+		require.Equal(t, d.Count(), decoded.Count())
+		require.Equal(t, d.Compression(), decoded.Compression())
+		for _, q := range []float64{0, 0.3, 0.5, 0.99, 1} {
+			require.Equal(t, d.Quantile(q), decoded.Quantile(q))
+		}
+
+		empty, _ := NewTDigest(50)
+		data, err = empty.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err = DecodeTDigest(data)
+		require.NoError(t, err)
+		require.Zero(t, decoded.Count())
+
+		_, err = DecodeTDigest(data[:10])
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+		data, _ = d.MarshalBinary()
+		corrupt := slices.Clone(data)
+		clear(corrupt[tdigestHeaderSize+8 : tdigestHeaderSize+16]) // zero weight
+		_, err = DecodeTDigest(corrupt)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+		_, err = DecodeTDigest(data[:len(data)-1])
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+	})
+
+	t.Run("edge cases", func(t *testing.T) {
+		_, err := NewTDigest(1)
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		_, err = NewTDigest(math.NaN())
+		require.ErrorIs(t, err, ErrInvalidParameter)
+
+		d, _ := NewTDigest(100)
+		require.True(t, math.IsNaN(d.Quantile(0.5)))
+		require.True(t, math.IsNaN(d.CDF(1)))
+		require.True(t, math.IsNaN(d.Min()))
+
+		d.Add(math.NaN())
+		d.Add(42)
+		require.Equal(t, uint64(1), d.Count())
+		require.Equal(t, 42.0, d.Quantile(0.5))
+		require.True(t, math.IsNaN(d.Quantile(1.5)))
+
+		d.Add(42)
+		d.Add(42)
+		require.Equal(t, 42.0, d.Quantile(0.99))
+	})
+}
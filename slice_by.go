@@ -0,0 +1,150 @@
+package gobag
+
+// SliceContainsBy checks if an element with the same key as target is present
+// in the source slice. The key of each element is computed with keyFn, which
+// allows slices of non-comparable types, such as structs holding slices or
+// maps, to be searched by a comparable field.
+func SliceContainsBy[T any, K comparable](source []T, target T, keyFn func(T) K) bool {
+	targetKey := keyFn(target)
+	for i := range source {
+		if keyFn(source[i]) == targetKey {
+			return true
+		}
+	}
+	return false
+}
+
+// SliceContainsFunc checks if an element equal to target, as reported by eq,
+// is present in the source slice.
+func SliceContainsFunc[T any](source []T, target T, eq func(a, b T) bool) bool {
+	for i := range source {
+		if eq(source[i], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// SliceExclusionBy performs an exclusion operation on two slices, source and
+// reference, comparing elements by the key computed with keyFn. It returns two
+// slices: elementsOnlyInSource contains the elements of source whose key is
+// not present in reference, and elementsOnlyInReference contains the elements
+// of reference whose key is not present in source. Only the first element for
+// each key is kept, and each result follows the order of its originating
+// slice.
+//
+// Example:
+//
+//	removed, added := SliceExclusionBy(previousUsers, currentUsers, func(u User) int { return u.ID })
+func SliceExclusionBy[T any, K comparable](source, reference []T, keyFn func(T) K) ([]T, []T) {
+	sourceKeys := keySet(source, keyFn)
+	referenceKeys := keySet(reference, keyFn)
+
+	elementsOnlyInSource := filterByKey(source, keyFn, func(key K) bool { return !referenceKeys.Has(key) })
+	elementsOnlyInReference := filterByKey(reference, keyFn, func(key K) bool { return !sourceKeys.Has(key) })
+	return elementsOnlyInSource, elementsOnlyInReference
+}
+
+// SliceExclusionFunc performs an exclusion operation on two slices, source and
+// reference, comparing elements with eq. It behaves like SliceExclusionBy but
+// runs in O(n*m) time since no key can be hashed.
+func SliceExclusionFunc[T any](source, reference []T, eq func(a, b T) bool) ([]T, []T) {
+	elementsOnlyInSource := filterFunc(source, eq, func(element T) bool {
+		return !SliceContainsFunc(reference, element, eq)
+	})
+	elementsOnlyInReference := filterFunc(reference, eq, func(element T) bool {
+		return !SliceContainsFunc(source, element, eq)
+	})
+	return elementsOnlyInSource, elementsOnlyInReference
+}
+
+// SliceIntersectionBy finds the intersection of two slices, source and
+// target, comparing elements by the key computed with keyFn. It returns the
+// elements of source whose key is also present in target. Only the first
+// element for each key is kept, in the order of source.
+func SliceIntersectionBy[T any, K comparable](source, target []T, keyFn func(T) K) []T {
+	targetKeys := keySet(target, keyFn)
+	return filterByKey(source, keyFn, targetKeys.Has)
+}
+
+// SliceIntersectionFunc finds the intersection of two slices, source and
+// target, comparing elements with eq. It behaves like SliceIntersectionBy but
+// runs in O(n*m) time since no key can be hashed.
+func SliceIntersectionFunc[T any](source, target []T, eq func(a, b T) bool) []T {
+	return filterFunc(source, eq, func(element T) bool {
+		return SliceContainsFunc(target, element, eq)
+	})
+}
+
+// FilterUniqueElementsBy returns a new slice containing the elements of the
+// given slice with a unique key, as computed by keyFn. Only the first
+// occurrence of each key is included, and the order of the original elements
+// is preserved.
+func FilterUniqueElementsBy[T any, K comparable](slice []T, keyFn func(T) K) []T {
+	seenKeys := &Set[K]{elements: make(map[K]struct{}, len(slice))}
+	uniqueSlice := make([]T, 0, len(slice))
+	for _, element := range slice {
+		key := keyFn(element)
+		if seenKeys.Has(key) {
+			continue
+		}
+		seenKeys.elements[key] = struct{}{}
+		uniqueSlice = append(uniqueSlice, element)
+	}
+	return uniqueSlice
+}
+
+// FilterUniqueElementsFunc returns a new slice containing the elements of the
+// given slice that are unique according to eq. Only the first occurrence of
+// each element is included, and the order of the original elements is
+// preserved. It runs in O(n^2) time.
+func FilterUniqueElementsFunc[T any](slice []T, eq func(a, b T) bool) []T {
+	uniqueSlice := make([]T, 0, len(slice))
+	for _, element := range slice {
+		if SliceContainsFunc(uniqueSlice, element, eq) {
+			continue
+		}
+		uniqueSlice = append(uniqueSlice, element)
+	}
+	return uniqueSlice
+}
+
+func keySet[T any, K comparable](slice []T, keyFn func(T) K) *Set[K] {
+	keys := &Set[K]{elements: make(map[K]struct{}, len(slice))}
+	for i := range slice {
+		keys.elements[keyFn(slice[i])] = struct{}{}
+	}
+	return keys
+}
+
+// filterByKey returns the first element for each key of slice for which keep
+// reports true, preserving the order of slice. It returns nil if no element is
+// kept.
+func filterByKey[T any, K comparable](slice []T, keyFn func(T) K, keep func(K) bool) []T {
+	seenKeys := NewSet[K]()
+
+	var result []T
+	for _, element := range slice {
+		key := keyFn(element)
+		if seenKeys.Has(key) || !keep(key) {
+			continue
+		}
+		seenKeys.Add(key)
+		result = append(result, element)
+	}
+	return result
+}
+
+// filterFunc returns the first element of each group of elements equal
+// according to eq for which keep reports true, preserving the order of slice.
+// It returns nil if no element is kept.
+func filterFunc[T any](slice []T, eq func(a, b T) bool, keep func(T) bool) []T {
+	var result []T
+	for _, element := range slice {
+		if SliceContainsFunc(result, element, eq) || !keep(element) {
+			continue
+		}
+		result = append(result, element)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package gobag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct {
+	ID    int
+	Name  string
+	Roles []string
+}
+
+func testUserID(u testUser) int {
+	return u.ID
+}
+
+func testUserEq(a, b testUser) bool {
+	return a.ID == b.ID
+}
+
+func TestSliceContainsBy(t *testing.T) {
+	users := []testUser{
+		{ID: 1, Name: "alice", Roles: []string{"admin"}},
+		{ID: 2, Name: "bob"},
+	}
+
+	require.True(t, SliceContainsBy(users, testUser{ID: 2}, testUserID))
+	require.False(t, SliceContainsBy(users, testUser{ID: 3}, testUserID))
+	require.True(t, SliceContainsFunc(users, testUser{ID: 1}, testUserEq))
+	require.False(t, SliceContainsFunc(users, testUser{ID: 3}, testUserEq))
+	require.False(t, SliceContainsFunc(nil, testUser{ID: 3}, testUserEq))
+}
+
+func TestSliceExclusionBy(t *testing.T) {
+	previous := []testUser{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol"},
+		{ID: 1, Name: "alice duplicate"},
+	}
+	current := []testUser{
+		{ID: 4, Name: "dave", Roles: []string{"ops"}},
+		{ID: 2, Name: "bob renamed"},
+		{ID: 5, Name: "erin"},
+	}
+	expectedRemoved := []testUser{
+		{ID: 1, Name: "alice"},
+		{ID: 3, Name: "carol"},
+	}
+	expectedAdded := []testUser{
+		{ID: 4, Name: "dave", Roles: []string{"ops"}},
+		{ID: 5, Name: "erin"},
+	}
+
+	t.Run("by key", func(t *testing.T) {
+		removed, added := SliceExclusionBy(previous, current, testUserID)
+		require.Equal(t, expectedRemoved, removed)
+		require.Equal(t, expectedAdded, added)
+	})
+
+	t.Run("by func", func(t *testing.T) {
+		removed, added := SliceExclusionFunc(previous, current, testUserEq)
+		require.Equal(t, expectedRemoved, removed)
+		require.Equal(t, expectedAdded, added)
+	})
+
+	t.Run("no difference", func(t *testing.T) {
+		removed, added := SliceExclusionBy(previous, previous, testUserID)
+		require.Nil(t, removed)
+		require.Nil(t, added)
+	})
+}
+
+func TestSliceIntersectionBy(t *testing.T) {
+	source := []testUser{
+		{ID: 3, Name: "carol"},
+		{ID: 1, Name: "alice"},
+		{ID: 3, Name: "carol duplicate"},
+	}
+	target := []testUser{
+		{ID: 1, Name: "alice renamed"},
+		{ID: 3},
+	}
+	expected := []testUser{
+		{ID: 3, Name: "carol"},
+		{ID: 1, Name: "alice"},
+	}
+
+	require.Equal(t, expected, SliceIntersectionBy(source, target, testUserID))
+	require.Equal(t, expected, SliceIntersectionFunc(source, target, testUserEq))
+	require.Nil(t, SliceIntersectionBy(source, nil, testUserID))
+	require.Nil(t, SliceIntersectionFunc(source, nil, testUserEq))
+}
+
+func TestFilterUniqueElementsBy(t *testing.T) {
+	users := []testUser{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 1, Name: "alice again"},
+		{ID: 3, Name: "carol"},
+		{ID: 2, Name: "bob again"},
+	}
+	expected := []testUser{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol"},
+	}
+
+	require.Equal(t, expected, FilterUniqueElementsBy(users, testUserID))
+	require.Equal(t, expected, FilterUniqueElementsFunc(users, testUserEq))
+	require.Equal(t, []testUser{}, FilterUniqueElementsBy([]testUser{}, testUserID))
+}
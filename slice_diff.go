@@ -0,0 +1,256 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrEditMismatch is returned by ApplyEdits when an edit script does not
+// describe the slice it is applied to.
+var ErrEditMismatch = errors.New("edit script does not match source slice")
+
+// EditOp is the kind of operation performed by an Edit.
+type EditOp int
+
+const (
+	// EditEqual keeps an element present in both slices.
+	EditEqual EditOp = iota
+	// EditDelete removes an element of the source slice.
+	EditDelete
+	// EditInsert adds an element of the target slice.
+	EditInsert
+)
+
+// String returns the name of the operation.
+func (op EditOp) String() string {
+	switch op {
+	case EditEqual:
+		return "equal"
+	case EditDelete:
+		return "delete"
+	case EditInsert:
+		return "insert"
+	default:
+		return fmt.Sprintf("EditOp(%d)", int(op))
+	}
+}
+
+// Edit is a single step of an edit script turning a source slice into a
+// target slice.
+type Edit[T comparable] struct {
+	// Op is the operation performed by the edit.
+	Op EditOp
+	// Value is the element kept, deleted or inserted.
+	Value T
+	// OldIndex is the index of Value in the source slice, or -1 for an
+	// insertion.
+	OldIndex int
+	// NewIndex is the index of Value in the target slice, or -1 for a
+	// deletion.
+	NewIndex int
+}
+
+// SliceDiff computes a minimal edit script turning a into b using Myers'
+// O(ND) difference algorithm, where N is the combined length of the slices
+// and D the number of inserted and deleted elements. Within each run of
+// changes, deletions come before insertions. It returns nil if both slices
+// are empty.
+//
+// Example:
+//
+//	edits := SliceDiff([]string{"a", "b", "c"}, []string{"a", "c", "d"})
+//	// equal a, delete b, equal c, insert d
+func SliceDiff[T comparable](a, b []T) []Edit[T] {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+
+	// trace[d] holds the furthest reaching x of each diagonal k in [-d, d]
+	// before the d-th round, which is all the backtracking needs.
+	var trace [][]int
+	finalD := 0
+search:
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				finalD = d
+				break search
+			}
+		}
+	}
+
+	var edits []Edit[T]
+	x, y := n, m
+	for d := finalD; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, Edit[T]{Op: EditEqual, Value: a[x], OldIndex: x, NewIndex: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, Edit[T]{Op: EditInsert, Value: b[y], OldIndex: -1, NewIndex: y})
+		} else {
+			x--
+			edits = append(edits, Edit[T]{Op: EditDelete, Value: a[x], OldIndex: x, NewIndex: -1})
+		}
+	}
+
+	slices.Reverse(edits)
+	return edits
+}
+
+// ApplyEdits reconstructs the target slice of an edit script from its source
+// slice a. It returns ErrEditMismatch if an equal or delete edit does not
+// match the corresponding element of a, or if the script does not consume a
+// entirely.
+func ApplyEdits[T comparable](a []T, edits []Edit[T]) ([]T, error) {
+	var result []T
+	position := 0
+	for i, edit := range edits {
+		switch edit.Op {
+		case EditEqual, EditDelete:
+			if position >= len(a) || a[position] != edit.Value {
+				return nil, fmt.Errorf("edit %d (%s at %d): %w", i, edit.Op, position, ErrEditMismatch)
+			}
+			if edit.Op == EditEqual {
+				result = append(result, edit.Value)
+			}
+			position++
+		case EditInsert:
+			result = append(result, edit.Value)
+		default:
+			return nil, fmt.Errorf("edit %d (%s): %w", i, edit.Op, ErrEditMismatch)
+		}
+	}
+	if position != len(a) {
+		return nil, fmt.Errorf("%d trailing elements not covered: %w", len(a)-position, ErrEditMismatch)
+	}
+	return result, nil
+}
+
+// UnifiedDiff renders the differences between the lines a and b in the
+// unified diff format, labelling them fromName and toName and surrounding each
+// change with up to contextLines unchanged lines. It returns an empty string
+// if a and b are equal.
+//
+// Example:
+//
+//	fmt.Print(UnifiedDiff("config.v1", "config.v2", oldLines, newLines, 3))
+func UnifiedDiff(fromName, toName string, a, b []string, contextLines int) string {
+	edits := SliceDiff(a, b)
+	contextLines = max(contextLines, 0)
+
+	// oldPositions[i] and newPositions[i] are the number of lines of a and b
+	// consumed before edits[i] is applied.
+	oldPositions := make([]int, len(edits)+1)
+	newPositions := make([]int, len(edits)+1)
+	for i, edit := range edits {
+		oldPositions[i+1], newPositions[i+1] = oldPositions[i], newPositions[i]
+		if edit.Op != EditInsert {
+			oldPositions[i+1]++
+		}
+		if edit.Op != EditDelete {
+			newPositions[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk while the next change is close enough for their
+		// contexts to touch.
+		start := max(i-contextLines, 0)
+		end := i
+		for end < len(edits) {
+			if edits[end].Op != EditEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Op == EditEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*contextLines {
+				end = min(end+contextLines, len(edits))
+				break
+			}
+			end = run
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			unifiedRange(oldPositions[start], oldPositions[end]-oldPositions[start]),
+			unifiedRange(newPositions[start], newPositions[end]-newPositions[start]),
+		)
+		for _, edit := range edits[start:end] {
+			switch edit.Op {
+			case EditEqual:
+				out.WriteString(" ")
+			case EditDelete:
+				out.WriteString("-")
+			case EditInsert:
+				out.WriteString("+")
+			}
+			out.WriteString(edit.Value)
+			out.WriteString("\n")
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// unifiedRange formats a hunk range following the GNU diff conventions: the
+// count is omitted when it is one, and an empty range refers to the line
+// preceding it.
+func unifiedRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
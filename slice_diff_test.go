@@ -0,0 +1,215 @@
+package gobag
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func countChanges[T comparable](edits []Edit[T]) int {
+	changes := 0
+	for _, edit := range edits {
+		if edit.Op != EditEqual {
+			changes++
+		}
+	}
+	return changes
+}
+
+// testLCSLength computes the length of the longest common subsequence of a
+// and b with the textbook dynamic programming algorithm.
+func testLCSLength(a, b []int) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	return lengths[0][0]
+}
+
+func TestSliceDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+		changes  int
+	}{
+		{
+			name:     "both empty",
+			a:        "",
+			b:        "",
+			expected: "",
+		},
+		{
+			name:     "only insertions",
+			a:        "",
+			b:        "abc",
+			expected: "+a+b+c",
+			changes:  3,
+		},
+		{
+			name:     "only deletions",
+			a:        "abc",
+			b:        "",
+			expected: "-a-b-c",
+			changes:  3,
+		},
+		{
+			name:     "equal",
+			a:        "abc",
+			b:        "abc",
+			expected: "=a=b=c",
+		},
+		{
+			name:     "myers paper example",
+			a:        "abcabba",
+			b:        "cbabac",
+			expected: "-a-b=c+b=a=b-b=a+c",
+			changes:  5,
+		},
+		{
+			name:     "replacement deletes before inserting",
+			a:        "axc",
+			b:        "ayc",
+			expected: "=a-x+y=c",
+			changes:  2,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			a := strings.Split(test.a, "")
+			b := strings.Split(test.b, "")
+			edits := SliceDiff(a, b)
+
+			var rendered strings.Builder
+			for _, edit := range edits {
+				rendered.WriteString(map[EditOp]string{EditEqual: "=", EditDelete: "-", EditInsert: "+"}[edit.Op])
+				rendered.WriteString(edit.Value)
+				switch edit.Op {
+				case EditEqual:
+					require.Equal(t, a[edit.OldIndex], edit.Value)
+					require.Equal(t, b[edit.NewIndex], edit.Value)
+				case EditDelete:
+					require.Equal(t, a[edit.OldIndex], edit.Value)
+					require.Equal(t, -1, edit.NewIndex)
+				case EditInsert:
+					require.Equal(t, -1, edit.OldIndex)
+					require.Equal(t, b[edit.NewIndex], edit.Value)
+				}
+			}
+			require.Equal(t, test.expected, rendered.String())
+			require.Equal(t, test.changes, countChanges(edits))
+		})
+	}
+}
+
+func TestApplyEdits(t *testing.T) {
+	t.Run("round trips random slices", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		for range 200 {
+			a := make([]int, r.IntN(30))
+			for i := range a {
+				a[i] = r.IntN(5)
+			}
+			b := make([]int, r.IntN(30))
+			for i := range b {
+				b[i] = r.IntN(5)
+			}
+
+			edits := SliceDiff(a, b)
+			result, err := ApplyEdits(a, edits)
+			require.NoError(t, err)
+			if len(b) == 0 {
+				require.Empty(t, result)
+			} else {
+				require.Equal(t, b, result)
+			}
+
+			// a minimal script keeps every element of a longest common
+			// subsequence and changes everything else.
+			require.Equal(t, len(a)+len(b)-2*testLCSLength(a, b), countChanges(edits))
+		}
+	})
+
+	t.Run("rejects mismatching scripts", func(t *testing.T) {
+		edits := SliceDiff([]string{"a", "b"}, []string{"a", "c"})
+
+		_, err := ApplyEdits([]string{"x", "b"}, edits)
+		require.True(t, errors.Is(err, ErrEditMismatch))
+
+		_, err = ApplyEdits([]string{"a", "b", "z"}, edits)
+		require.True(t, errors.Is(err, ErrEditMismatch))
+
+		_, err = ApplyEdits([]string{"a"}, edits)
+		require.True(t, errors.Is(err, ErrEditMismatch))
+	})
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		lines := []string{"a", "b"}
+		require.Equal(t, "", UnifiedDiff("old", "new", lines, lines, 3))
+	})
+
+	t.Run("single hunk", func(t *testing.T) {
+		a := []string{"host=localhost", "port=80", "debug=false"}
+		b := []string{"host=localhost", "port=8080", "debug=false", "tls=true"}
+		expected := strings.Join([]string{
+			"--- config.v1",
+			"+++ config.v2",
+			"@@ -1,3 +1,4 @@",
+			" host=localhost",
+			"-port=80",
+			"+port=8080",
+			" debug=false",
+			"+tls=true",
+			"",
+		}, "\n")
+		require.Equal(t, expected, UnifiedDiff("config.v1", "config.v2", a, b, 3))
+	})
+
+	t.Run("separate hunks", func(t *testing.T) {
+		a := strings.Split("abcdefghij", "")
+		b := strings.Split("aXcdefghiY", "")
+		expected := strings.Join([]string{
+			"--- a",
+			"+++ b",
+			"@@ -1,3 +1,3 @@",
+			" a",
+			"-b",
+			"+X",
+			" c",
+			"@@ -9,2 +9,2 @@",
+			" i",
+			"-j",
+			"+Y",
+			"",
+		}, "\n")
+		require.Equal(t, expected, UnifiedDiff("a", "b", a, b, 1))
+	})
+
+	t.Run("empty ranges", func(t *testing.T) {
+		expected := strings.Join([]string{
+			"--- a",
+			"+++ b",
+			"@@ -0,0 +1 @@",
+			"+x",
+			"",
+		}, "\n")
+		require.Equal(t, expected, UnifiedDiff("a", "b", nil, []string{"x"}, 3))
+	})
+}
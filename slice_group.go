@@ -0,0 +1,93 @@
+package gobag
+
+// The helpers in this file follow a single nil-handling convention: a nil
+// input slice yields nil results, while an empty but non-nil input yields
+// empty but non-nil results. This keeps the distinction tested by the
+// testEqSlice helpers intact through a transformation.
+
+// GroupBy splits the elements of slice into groups sharing the same key, as
+// computed by keyFn. The elements of each group keep their relative order
+// from slice.
+//
+// Example:
+//
+//	byCountry := GroupBy(users, func(u User) string { return u.Country })
+func GroupBy[T any, K comparable](slice []T, keyFn func(T) K) map[K][]T {
+	if slice == nil {
+		return nil
+	}
+	groups := make(map[K][]T)
+	for _, element := range slice {
+		key := keyFn(element)
+		groups[key] = append(groups[key], element)
+	}
+	return groups
+}
+
+// Partition splits the elements of slice in two: matching contains the
+// elements for which predicate reports true and rest contains the others.
+// Both results keep the relative order of slice.
+func Partition[T any](slice []T, predicate func(T) bool) (matching, rest []T) {
+	if slice == nil {
+		return nil, nil
+	}
+	matching, rest = make([]T, 0), make([]T, 0)
+	for _, element := range slice {
+		if predicate(element) {
+			matching = append(matching, element)
+		} else {
+			rest = append(rest, element)
+		}
+	}
+	return matching, rest
+}
+
+// KeyBy indexes the elements of slice by the key computed with keyFn. When
+// several elements share a key, the first one is kept, consistent with
+// FilterUniqueElementsBy.
+func KeyBy[T any, K comparable](slice []T, keyFn func(T) K) map[K]T {
+	if slice == nil {
+		return nil
+	}
+	index := make(map[K]T, len(slice))
+	for _, element := range slice {
+		key := keyFn(element)
+		if _, ok := index[key]; ok {
+			continue
+		}
+		index[key] = element
+	}
+	return index
+}
+
+// CountBy counts the elements of slice sharing the same key, as computed by
+// keyFn.
+func CountBy[T any, K comparable](slice []T, keyFn func(T) K) map[K]int {
+	if slice == nil {
+		return nil
+	}
+	counts := make(map[K]int)
+	for _, element := range slice {
+		counts[keyFn(element)]++
+	}
+	return counts
+}
+
+// Chunk splits slice into consecutive chunks of size elements. The last chunk
+// may hold fewer than size elements. The chunks share the backing array of
+// slice but have their capacity capped, so appending to a chunk never
+// overwrites the next one. Chunk panics if size is less than 1.
+func Chunk[T any](slice []T, size int) [][]T {
+	if size < 1 {
+		panic("gobag: chunk size must be at least 1")
+	}
+	if slice == nil {
+		return nil
+	}
+	chunks := make([][]T, 0, (len(slice)+size-1)/size)
+	for start := 0; start < len(slice); start += size {
+		end := min(start+size, len(slice))
+		chunks = append(chunks, slice[start:end:end])
+	}
+	return chunks
+}
@@ -0,0 +1,80 @@
+package gobag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot"}
+	groups := GroupBy(words, func(s string) byte { return s[0] })
+	require.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado", "apricot"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}, groups)
+
+	require.Nil(t, GroupBy([]string(nil), strings.ToUpper))
+	require.Equal(t, map[string][]string{}, GroupBy([]string{}, strings.ToUpper))
+}
+
+func TestPartition(t *testing.T) {
+	isEven := func(i int) bool { return i%2 == 0 }
+
+	even, odd := Partition([]int{5, 2, 3, 8, 6, 1}, isEven)
+	require.Equal(t, []int{2, 8, 6}, even)
+	require.Equal(t, []int{5, 3, 1}, odd)
+
+	even, odd = Partition([]int{2}, isEven)
+	require.Equal(t, []int{2}, even)
+	require.NotNil(t, odd)
+	require.Empty(t, odd)
+
+	even, odd = Partition(nil, isEven)
+	require.Nil(t, even)
+	require.Nil(t, odd)
+
+	even, odd = Partition([]int{}, isEven)
+	require.True(t, testEqSliceInts([]int{}, even))
+	require.True(t, testEqSliceInts([]int{}, odd))
+}
+
+func TestKeyBy(t *testing.T) {
+	users := []testUser{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 1, Name: "alice again"},
+	}
+	require.Equal(t, map[int]testUser{
+		1: {ID: 1, Name: "alice"},
+		2: {ID: 2, Name: "bob"},
+	}, KeyBy(users, testUserID))
+
+	require.Nil(t, KeyBy([]testUser(nil), testUserID))
+	require.Equal(t, map[int]testUser{}, KeyBy([]testUser{}, testUserID))
+}
+
+func TestCountBy(t *testing.T) {
+	words := []string{"a", "bb", "cc", "ddd", "e"}
+	require.Equal(t, map[int]int{1: 2, 2: 2, 3: 1}, CountBy(words, func(s string) int { return len(s) }))
+
+	require.Nil(t, CountBy([]string(nil), strings.ToUpper))
+	require.Equal(t, map[string]int{}, CountBy([]string{}, strings.ToUpper))
+}
+
+func TestChunk(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	chunks := Chunk(input, 2)
+	require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+
+	// appending to a chunk must not clobber the following one
+	_ = append(chunks[0], 99)
+	require.Equal(t, []int{3, 4}, chunks[1])
+
+	require.Equal(t, [][]int{{1, 2, 3, 4, 5}}, Chunk(input, 10))
+	require.Nil(t, Chunk([]int(nil), 2))
+	require.Equal(t, [][]int{}, Chunk([]int{}, 2))
+	require.Panics(t, func() { Chunk(input, 0) })
+}
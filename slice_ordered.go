@@ -0,0 +1,127 @@
+package gobag
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SliceIntersectionOrdered finds the intersection of two slices, source and
+// target, like SliceIntersection, but returns the elements in the order in
+// which they are first seen in source. Each element is included at most once.
+// It returns nil if the intersection is empty.
+func SliceIntersectionOrdered[T comparable](source, target []T) []T {
+	targetSet := NewSet(target...)
+	seenElements := NewSet[T]()
+
+	var intersection []T
+	for _, element := range source {
+		if !targetSet.Has(element) || seenElements.Has(element) {
+			continue
+		}
+		seenElements.Add(element)
+		intersection = append(intersection, element)
+	}
+	return intersection
+}
+
+// SliceExclusionOrdered performs an exclusion operation on two slices, source
+// and reference, like SliceExclusion, but returns the elements in the order in
+// which they are first seen in their respective slice: elementsOnlyInSource
+// follows the order of source and elementsOnlyInReference follows the order of
+// reference. Each element is included at most once.
+func SliceExclusionOrdered[T comparable](source, reference []T) ([]T, []T) {
+	sourceSet := NewSet(source...)
+	referenceSet := NewSet(reference...)
+
+	elementsOnlyInSource := filterOrderedExcluding(source, referenceSet)
+	elementsOnlyInReference := filterOrderedExcluding(reference, sourceSet)
+	return elementsOnlyInSource, elementsOnlyInReference
+}
+
+// SliceIntersectionSorted finds the intersection of two slices, source and
+// target, and returns the unique common elements sorted in ascending order.
+// It returns nil if the intersection is empty.
+func SliceIntersectionSorted[T cmp.Ordered](source, target []T) []T {
+	intersection := SliceIntersection(source, target)
+	slices.Sort(intersection)
+	return intersection
+}
+
+// SliceExclusionSorted performs an exclusion operation on two slices, source
+// and reference, and returns the unique elements only in source and the
+// unique elements only in reference, each sorted in ascending order.
+func SliceExclusionSorted[T cmp.Ordered](source, reference []T) ([]T, []T) {
+	elementsOnlyInSource, elementsOnlyInReference := SliceExclusion(source, reference)
+	slices.Sort(elementsOnlyInSource)
+	slices.Sort(elementsOnlyInReference)
+	return elementsOnlyInSource, elementsOnlyInReference
+}
+
+// SliceIntersectionMultiset finds the multiset intersection of two slices,
+// source and target. Duplicates are respected: an element appearing n times in
+// source and m times in target appears min(n, m) times in the result. The
+// elements are returned in the order in which they appear in source. It
+// returns nil if the intersection is empty.
+//
+// Example:
+//
+//	SliceIntersectionMultiset([]string{"a", "a", "b"}, []string{"a", "a"}) // [a a]
+func SliceIntersectionMultiset[T comparable](source, target []T) []T {
+	remaining := countElements(target)
+
+	var intersection []T
+	for _, element := range source {
+		if remaining[element] == 0 {
+			continue
+		}
+		remaining[element]--
+		intersection = append(intersection, element)
+	}
+	return intersection
+}
+
+// SliceExclusionMultiset performs a multiset exclusion operation on two
+// slices, source and reference. Duplicates are respected: an element appearing
+// n times in source and m times in reference appears n-m times in
+// elementsOnlyInSource when n > m, and m-n times in elementsOnlyInReference
+// when m > n. Each result keeps the order of its originating slice, with the
+// first occurrences being the ones cancelled out.
+func SliceExclusionMultiset[T comparable](source, reference []T) ([]T, []T) {
+	elementsOnlyInSource := subtractMultiset(source, countElements(reference))
+	elementsOnlyInReference := subtractMultiset(reference, countElements(source))
+	return elementsOnlyInSource, elementsOnlyInReference
+}
+
+func filterOrderedExcluding[T comparable](slice []T, excluded *Set[T]) []T {
+	seenElements := NewSet[T]()
+
+	var result []T
+	for _, element := range slice {
+		if excluded.Has(element) || seenElements.Has(element) {
+			continue
+		}
+		seenElements.Add(element)
+		result = append(result, element)
+	}
+	return result
+}
+
+func countElements[T comparable](slice []T) map[T]int {
+	counts := make(map[T]int, len(slice))
+	for _, element := range slice {
+		counts[element]++
+	}
+	return counts
+}
+
+func subtractMultiset[T comparable](slice []T, subtrahend map[T]int) []T {
+	var result []T
+	for _, element := range slice {
+		if subtrahend[element] > 0 {
+			subtrahend[element]--
+			continue
+		}
+		result = append(result, element)
+	}
+	return result
+}
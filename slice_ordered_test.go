@@ -0,0 +1,117 @@
+package gobag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceIntersectionOrdered(t *testing.T) {
+	t.Run("using ints", func(t *testing.T) {
+		source := []int{5, 3, 1, 3, 2}
+		target := []int{1, 2, 3, 4}
+
+		result := SliceIntersectionOrdered(source, target)
+		require.Equal(t, []int{3, 1, 2}, result)
+	})
+
+	t.Run("using strings", func(t *testing.T) {
+		source := []string{"c", "b", "a"}
+		target := []string{"a", "b", "d"}
+
+		result := SliceIntersectionOrdered(source, target)
+		require.Equal(t, []string{"b", "a"}, result)
+	})
+
+	t.Run("empty intersection is nil", func(t *testing.T) {
+		require.Nil(t, SliceIntersectionOrdered([]int{1}, []int{2}))
+		require.Nil(t, SliceIntersectionOrdered(nil, []int{2}))
+	})
+
+	t.Run("deprecated helpers keep source order", func(t *testing.T) {
+		require.Equal(t, []string{"c", "b"}, SliceIntersectStrings([]string{"c", "b", "a"}, []string{"b", "c"}))
+		require.Equal(t, []int{3, 2}, SliceIntersectInts([]int{3, 2, 1}, []int{2, 3}))
+	})
+}
+
+func TestSliceExclusionOrdered(t *testing.T) {
+	source := []string{"z", "a", "z", "b", "c"}
+	reference := []string{"d", "c", "b", "e", "d"}
+
+	left, right := SliceExclusionOrdered(source, reference)
+	require.Equal(t, []string{"z", "a"}, left)
+	require.Equal(t, []string{"d", "e"}, right)
+
+	leftInts, rightInts := SliceExclusionOrdered([]int{1, 2}, []int{2, 1})
+	require.Nil(t, leftInts)
+	require.Nil(t, rightInts)
+
+	left, right = SliceExclusionStrings([]string{"c", "a", "b"}, []string{"b"})
+	require.Equal(t, []string{"c", "a"}, left)
+	require.Nil(t, right)
+
+	leftInts, rightInts = SliceExclusionInts([]int{3, 1}, []int{9, 1, 7})
+	require.Equal(t, []int{3}, leftInts)
+	require.Equal(t, []int{9, 7}, rightInts)
+}
+
+func TestSliceIntersectionSorted(t *testing.T) {
+	result := SliceIntersectionSorted([]int{9, 1, 5, 3, 5}, []int{5, 3, 9, 10})
+	require.Equal(t, []int{3, 5, 9}, result)
+
+	require.Nil(t, SliceIntersectionSorted([]string{"a"}, []string{"b"}))
+}
+
+func TestSliceExclusionSorted(t *testing.T) {
+	left, right := SliceExclusionSorted([]string{"d", "a", "c"}, []string{"c", "z", "b"})
+	require.Equal(t, []string{"a", "d"}, left)
+	require.Equal(t, []string{"b", "z"}, right)
+}
+
+func TestSliceIntersectionMultiset(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   []string
+		target   []string
+		expected []string
+	}{
+		{
+			name:     "duplicates in both",
+			source:   []string{"a", "a", "b"},
+			target:   []string{"a", "a"},
+			expected: []string{"a", "a"},
+		},
+		{
+			name:     "fewer duplicates in target",
+			source:   []string{"a", "b", "a", "a"},
+			target:   []string{"b", "a", "a"},
+			expected: []string{"a", "b", "a"},
+		},
+		{
+			name:     "no common elements",
+			source:   []string{"a"},
+			target:   []string{"b"},
+			expected: nil,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, SliceIntersectionMultiset(test.source, test.target))
+		})
+	}
+}
+
+func TestSliceExclusionMultiset(t *testing.T) {
+	source := []int{1, 1, 1, 2, 3}
+	reference := []int{1, 3, 3, 4}
+
+	left, right := SliceExclusionMultiset(source, reference)
+	require.Equal(t, []int{1, 1, 2}, left)
+	require.Equal(t, []int{3, 4}, right)
+
+	left, right = SliceExclusionMultiset([]int{1, 1}, []int{1, 1})
+	require.Nil(t, left)
+	require.Nil(t, right)
+}
@@ -21,25 +21,11 @@ func SliceContains[T comparable](source []T, target T) bool {
 // not in sourceSlice. The elements must be of a comparable type, denoted by
 // the type parameter T.
 func SliceExclusion[T comparable](source, reference []T) ([]T, []T) {
-	presenceMap := make(map[T]uint8)
-	for _, k := range source {
-		presenceMap[k] |= (1 << 0)
-	}
-	for _, k := range reference {
-		presenceMap[k] |= (1 << 1)
-	}
+	sourceSet := NewSet(source...)
+	referenceSet := NewSet(reference...)
 
-	var elementsOnlyInSource, elementsOnlyInReference []T
-	for element, presence := range presenceMap {
-		isPresentInSource := presence&(1<<0) != 0
-		isPresentInReference := presence&(1<<1) != 0
-		switch {
-		case isPresentInSource && !isPresentInReference:
-			elementsOnlyInSource = append(elementsOnlyInSource, element)
-		case !isPresentInSource && isPresentInReference:
-			elementsOnlyInReference = append(elementsOnlyInReference, element)
-		}
-	}
+	elementsOnlyInSource := sourceSet.Difference(referenceSet).Slice()
+	elementsOnlyInReference := referenceSet.Difference(sourceSet).Slice()
 	return elementsOnlyInSource, elementsOnlyInReference
 }
 
@@ -48,30 +34,7 @@ func SliceExclusion[T comparable](source, reference []T) ([]T, []T) {
 // and target. The elements must be of a comparable type, denoted by the type
 // parameter T.
 func SliceIntersection[T comparable](source, target []T) []T {
-	// Create a map to track the presence of elements in the two slices
-	presenceMap := make(map[T]uint8)
-	for _, element := range source {
-		presenceMap[element] |= (1 << 0)
-	}
-
-	// Process sourceSlice and mark elements as present in sourceSlice
-	for _, element := range target {
-		presenceMap[element] |= (1 << 1)
-	}
-
-	var intersection []T
-	for element, presence := range presenceMap {
-		isPresentInSource := presence&(1<<0) != 0
-		isPresentInTarget := presence&(1<<1) != 0
-		switch {
-		case isPresentInSource && isPresentInTarget:
-			intersection = append(intersection, element)
-		default:
-			continue
-		}
-	}
-
-	return intersection
+	return NewSet(source...).Intersect(NewSet(target...)).Slice()
 }
 
 // FilterUniqueElements returns a new slice containing unique elements from the
@@ -85,13 +48,13 @@ func SliceIntersection[T comparable](source, target []T) []T {
 // slice contains the unique elements in the same order as they appear in the
 // original slice.
 func FilterUniqueElements[T comparable](slice []T) []T {
-	seenElements := make(map[T]struct{}, len(slice))
+	seenElements := &Set[T]{elements: make(map[T]struct{}, len(slice))}
 	uniqueSlice := make([]T, 0, len(slice))
 	for _, element := range slice {
-		if _, ok := seenElements[element]; ok {
+		if seenElements.Has(element) {
 			continue
 		}
-		seenElements[element] = struct{}{}
+		seenElements.elements[element] = struct{}{}
 		uniqueSlice = append(uniqueSlice, element)
 	}
 	return uniqueSlice
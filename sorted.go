@@ -0,0 +1,200 @@
+package gobag
+
+import (
+	"cmp"
+	"container/heap"
+)
+
+// gallopRatio is the size ratio between two sorted slices above which
+// galloping search through the larger one beats a linear merge.
+const gallopRatio = 32
+
+// SortedIntersect finds the intersection of two slices sorted in ascending
+// order. It returns the common elements, deduplicated and sorted, or nil if
+// there are none. It runs in O(n+m) time, or O(n log(m/n)) when one slice is
+// much smaller than the other, without allocating anything but the result.
+func SortedIntersect[T cmp.Ordered](a, b []T) []T {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	var intersection []T
+	if len(a)*gallopRatio < len(b) {
+		j := 0
+		for i := 0; i < len(a) && j < len(b); i++ {
+			if i > 0 && a[i] == a[i-1] {
+				continue
+			}
+			j = gallop(b, j, a[i])
+			if j < len(b) && b[j] == a[i] {
+				intersection = append(intersection, a[i])
+			}
+		}
+		return intersection
+	}
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch c := cmp.Compare(a[i], b[j]); {
+		case c < 0:
+			i++
+		case c > 0:
+			j++
+		default:
+			intersection = appendUnique(intersection, a[i])
+			i++
+			j++
+		}
+	}
+	return intersection
+}
+
+// SortedUnion merges two slices sorted in ascending order into the sorted,
+// deduplicated union of their elements. It returns nil if both are empty.
+func SortedUnion[T cmp.Ordered](a, b []T) []T {
+	var union []T
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp.Compare(a[i], b[j]); {
+		case c < 0:
+			union = appendUnique(union, a[i])
+			i++
+		case c > 0:
+			union = appendUnique(union, b[j])
+			j++
+		default:
+			union = appendUnique(union, a[i])
+			i++
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		union = appendUnique(union, a[i])
+	}
+	for ; j < len(b); j++ {
+		union = appendUnique(union, b[j])
+	}
+	return union
+}
+
+// SortedDifference returns the sorted, deduplicated elements of a that are
+// not present in b, both slices being sorted in ascending order. It returns
+// nil if there are none. Like SortedIntersect, it gallops through b when a is
+// much smaller.
+func SortedDifference[T cmp.Ordered](a, b []T) []T {
+	var difference []T
+	gallops := len(a)*gallopRatio < len(b)
+	j := 0
+	for i := range a {
+		if i > 0 && a[i] == a[i-1] {
+			continue
+		}
+		if gallops {
+			j = gallop(b, j, a[i])
+		} else {
+			for j < len(b) && cmp.Less(b[j], a[i]) {
+				j++
+			}
+		}
+		if j == len(b) || b[j] != a[i] {
+			difference = append(difference, a[i])
+		}
+	}
+	return difference
+}
+
+// SortedMerge merges any number of slices sorted in ascending order into a
+// single sorted slice. Duplicates are kept; pass the result to SortedDedupe to
+// drop them. It runs in O(n log k) time for n elements spread over k slices,
+// using O(k) memory beyond the result, and returns nil if there are no
+// elements.
+func SortedMerge[T cmp.Ordered](sortedSlices ...[]T) []T {
+	total := 0
+	cursors := make(mergeCursors[T], 0, len(sortedSlices))
+	for _, s := range sortedSlices {
+		total += len(s)
+		if len(s) > 0 {
+			cursors = append(cursors, s)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	merged := make([]T, 0, total)
+	heap.Init(&cursors)
+	for len(cursors) > 0 {
+		merged = append(merged, cursors[0][0])
+		if cursors[0] = cursors[0][1:]; len(cursors[0]) == 0 {
+			heap.Pop(&cursors)
+		} else {
+			heap.Fix(&cursors, 0)
+		}
+	}
+	return merged
+}
+
+// SortedDedupe returns a new slice containing the elements of the given slice,
+// sorted in ascending order, with duplicates removed. It is the linear-time
+// counterpart of FilterUniqueElements for sorted input.
+func SortedDedupe[T cmp.Ordered](slice []T) []T {
+	uniqueSlice := make([]T, 0, len(slice))
+	for _, element := range slice {
+		uniqueSlice = appendUnique(uniqueSlice, element)
+	}
+	return uniqueSlice
+}
+
+// appendUnique appends element to the sorted slice unless it equals its last
+// element.
+func appendUnique[T cmp.Ordered](sorted []T, element T) []T {
+	if len(sorted) > 0 && sorted[len(sorted)-1] == element {
+		return sorted
+	}
+	return append(sorted, element)
+}
+
+// gallop returns the index of the first element of sorted[from:] that is not
+// less than target, or len(sorted) if there is none. It probes exponentially
+// growing steps before binary searching the last one, so finding an element d
+// positions ahead costs O(log d).
+func gallop[T cmp.Ordered](sorted []T, from int, target T) int {
+	if from >= len(sorted) || !cmp.Less(sorted[from], target) {
+		return from
+	}
+
+	// sorted[low] < target is invariant.
+	low, step := from, 1
+	high := low + step
+	for high < len(sorted) && cmp.Less(sorted[high], target) {
+		low = high
+		step *= 2
+		high = low + step
+	}
+	high = min(high, len(sorted))
+
+	for low+1 < high {
+		middle := int(uint(low+high) >> 1)
+		if cmp.Less(sorted[middle], target) {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return high
+}
+
+// mergeCursors is a min-heap of non-empty sorted slices ordered by their
+// first element.
+type mergeCursors[T cmp.Ordered] [][]T
+
+func (c mergeCursors[T]) Len() int           { return len(c) }
+func (c mergeCursors[T]) Less(i, j int) bool { return cmp.Less(c[i][0], c[j][0]) }
+func (c mergeCursors[T]) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c *mergeCursors[T]) Push(x any)        { *c = append(*c, x.([]T)) }
+
+func (c *mergeCursors[T]) Pop() any {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
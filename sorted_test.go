@@ -0,0 +1,158 @@
+package gobag
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomSortedInts(r *rand.Rand, n, maxValue int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.IntN(maxValue)
+	}
+	slices.Sort(s)
+	return s
+}
+
+func TestSortedSetOperations(t *testing.T) {
+	t.Run("examples", func(t *testing.T) {
+		a := []int{1, 2, 2, 4, 6, 8}
+		b := []int{2, 3, 4, 4, 9}
+
+		require.Equal(t, []int{2, 4}, SortedIntersect(a, b))
+		require.Equal(t, []int{1, 2, 3, 4, 6, 8, 9}, SortedUnion(a, b))
+		require.Equal(t, []int{1, 6, 8}, SortedDifference(a, b))
+		require.Equal(t, []int{3, 9}, SortedDifference(b, a))
+		require.Equal(t, []int{1, 2, 4, 6, 8}, SortedDedupe(a))
+	})
+
+	t.Run("empty inputs", func(t *testing.T) {
+		require.Nil(t, SortedIntersect([]int{1}, nil))
+		require.Nil(t, SortedUnion[int](nil, nil))
+		require.Equal(t, []int{1}, SortedUnion(nil, []int{1}))
+		require.Nil(t, SortedDifference(nil, []int{1}))
+		require.Equal(t, []int{1}, SortedDifference([]int{1}, nil))
+		require.Equal(t, []int{}, SortedDedupe([]int(nil)))
+		require.Nil(t, SortedMerge[int]())
+		require.Nil(t, SortedMerge([]int{}, nil))
+	})
+
+	t.Run("match the map based versions", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(3, 4))
+		for range 300 {
+			// mix balanced sizes with very unbalanced ones to exercise
+			// the galloping paths
+			a := randomSortedInts(r, r.IntN(20), 500)
+			b := randomSortedInts(r, r.IntN(2000), 500)
+			if r.IntN(2) == 0 {
+				a, b = b, a
+			}
+
+			require.Equal(t, SliceIntersectionSorted(a, b), SortedIntersect(a, b))
+
+			expectedUnion := FilterUniqueElements(append(slices.Clone(a), b...))
+			slices.Sort(expectedUnion)
+			if len(expectedUnion) == 0 {
+				expectedUnion = nil
+			}
+			require.Equal(t, expectedUnion, SortedUnion(a, b))
+
+			expectedDifference, _ := SliceExclusionSorted(a, b)
+			require.Equal(t, expectedDifference, SortedDifference(a, b))
+
+			expectedDedupe := FilterUniqueElements(a)
+			require.Equal(t, expectedDedupe, SortedDedupe(a))
+		}
+	})
+
+	t.Run("k-way merge", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(5, 6))
+		inputs := make([][]int, 7)
+		var expected []int
+		for i := range inputs {
+			inputs[i] = randomSortedInts(r, r.IntN(50), 100)
+			expected = append(expected, inputs[i]...)
+		}
+		slices.Sort(expected)
+
+		require.Equal(t, expected, SortedMerge(inputs...))
+		require.Equal(t, []string{"a", "b", "b", "c"}, SortedMerge([]string{"b", "c"}, []string{"a", "b"}))
+	})
+}
+
+func TestGallop(t *testing.T) {
+	sorted := []int{1, 3, 3, 5, 7, 9, 11}
+	for from := range len(sorted) + 1 {
+		for target := 0; target <= 12; target++ {
+			expected := from
+			for expected < len(sorted) && sorted[expected] < target {
+				expected++
+			}
+			require.Equal(t, expected, gallop(sorted, from, target), "from %d target %d", from, target)
+		}
+	}
+}
+
+func benchmarkSortedInputs(smallSize, largeSize int) ([]int, []int) {
+	r := rand.New(rand.NewPCG(7, 8))
+	return randomSortedInts(r, smallSize, 10*largeSize), randomSortedInts(r, largeSize, 10*largeSize)
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	for _, sizes := range []struct {
+		name         string
+		small, large int
+	}{
+		{name: "balanced", small: 100000, large: 100000},
+		{name: "unbalanced", small: 100, large: 1000000},
+	} {
+		a, c := benchmarkSortedInputs(sizes.small, sizes.large)
+		b.Run(sizes.name+"/SortedIntersect", func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				SortedIntersect(a, c)
+			}
+		})
+		b.Run(sizes.name+"/SliceIntersection", func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				SliceIntersection(a, c)
+			}
+		})
+	}
+}
+
+func BenchmarkDifference(b *testing.B) {
+	a, c := benchmarkSortedInputs(100000, 100000)
+	b.Run("SortedDifference", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			SortedDifference(a, c)
+		}
+	})
+	b.Run("SliceExclusion", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			SliceExclusion(a, c)
+		}
+	})
+}
+
+func BenchmarkDedupe(b *testing.B) {
+	a, _ := benchmarkSortedInputs(100000, 100000)
+	b.Run("SortedDedupe", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			SortedDedupe(a)
+		}
+	})
+	b.Run("FilterUniqueElements", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			FilterUniqueElements(a)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package gobag
+
+import "math"
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of the elements of s, computed in T, so that a sum of
+// integers may overflow.
+func Sum[T Number](s []T) T {
+	var sum T
+	for _, v := range s {
+		sum += v
+	}
+	return sum
+}
+
+// Mean returns the arithmetic mean of the elements of s, or NaN if s is empty.
+// It is computed incrementally in float64, so it does not overflow where Sum
+// would.
+func Mean[T Number](s []T) float64 {
+	stats := RunningStatsOf(s)
+	return stats.Mean()
+}
+
+// Min returns the smallest element of s. The boolean result is false if s is
+// empty. Unlike slices.Min, it does not panic on an empty slice.
+func Min[T Number](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	m := s[0]
+	for _, v := range s[1:] {
+		m = min(m, v)
+	}
+	return m, true
+}
+
+// Max returns the largest element of s. The boolean result is false if s is
+// empty. Unlike slices.Max, it does not panic on an empty slice.
+func Max[T Number](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	m := s[0]
+	for _, v := range s[1:] {
+		m = max(m, v)
+	}
+	return m, true
+}
+
+// Variance returns the sample variance of the elements of s, dividing by
+// len(s)-1, or NaN if s holds fewer than two elements. It uses the
+// numerically stable algorithm of Welford.
+func Variance[T Number](s []T) float64 {
+	stats := RunningStatsOf(s)
+	return stats.Variance()
+}
+
+// StdDev returns the sample standard deviation of the elements of s, the
+// square root of their Variance.
+func StdDev[T Number](s []T) float64 {
+	return math.Sqrt(Variance(s))
+}
+
+// WeightedMean returns the mean of values, each value weighing the matching
+// element of weights. It returns ErrInvalidWeights if the slices differ in
+// length, or if a weight is negative or not finite, or if they are all zero.
+func WeightedMean[T, W Number](values []T, weights []W) (float64, error) {
+	if len(values) != len(weights) {
+		return 0, ErrInvalidWeights
+	}
+	// West's weighted variant of Welford's incremental mean.
+	mean, total := 0.0, 0.0
+	for i, v := range values {
+		w := float64(weights[i])
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return 0, ErrInvalidWeights
+		}
+		if w == 0 {
+			continue
+		}
+		total += w
+		mean += (float64(v) - mean) * w / total
+	}
+	if total == 0 || math.IsInf(total, 0) {
+		return 0, ErrInvalidWeights
+	}
+	return mean, nil
+}
+
+// RunningStats accumulates the count, sum, mean, variance and extremes of a
+// stream of values in constant memory, using the algorithm of Welford.
+// Accumulators filled separately, for instance by several goroutines each
+// handling a share of the values, can be combined with Merge.
+//
+// The zero value of RunningStats holds no values and is ready to use. A
+// RunningStats is not safe for concurrent use without external
+// synchronization.
+type RunningStats struct {
+	count    uint64
+	sum      float64
+	mean     float64
+	m2       float64
+	min, max float64
+}
+
+// RunningStatsOf returns a RunningStats holding the elements of s.
+func RunningStatsOf[T Number](s []T) RunningStats {
+	var stats RunningStats
+	for _, v := range s {
+		stats.Add(float64(v))
+	}
+	return stats
+}
+
+// Add adds value to the accumulator.
+func (s *RunningStats) Add(value float64) {
+	if s.count == 0 {
+		s.min, s.max = value, value
+	} else {
+		s.min, s.max = min(s.min, value), max(s.max, value)
+	}
+	s.count++
+	s.sum += value
+	delta := value - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (value - s.mean)
+}
+
+// Merge adds the values accumulated by other to s, as if they had been added
+// to s one by one, using the pairwise update of Chan, Golub and LeVeque.
+//
+// Example:
+//
+//	var total gobag.RunningStats
+//	for _, shard := range shards { // each filled by its own goroutine
+//		total.Merge(shard)
+//	}
+func (s *RunningStats) Merge(other RunningStats) {
+	switch {
+	case other.count == 0:
+		return
+	case s.count == 0:
+		*s = other
+		return
+	}
+	count := s.count + other.count
+	delta := other.mean - s.mean
+	s.mean += delta * float64(other.count) / float64(count)
+	s.m2 += other.m2 + delta*delta*float64(s.count)*float64(other.count)/float64(count)
+	s.count = count
+	s.sum += other.sum
+	s.min, s.max = min(s.min, other.min), max(s.max, other.max)
+}
+
+// Count returns the number of values added.
+func (s *RunningStats) Count() uint64 {
+	return s.count
+}
+
+// Sum returns the sum of the values added.
+func (s *RunningStats) Sum() float64 {
+	return s.sum
+}
+
+// Mean returns the mean of the values added, or NaN if there are none.
+func (s *RunningStats) Mean() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+// Variance returns the sample variance of the values added, or NaN if there
+// are fewer than two.
+func (s *RunningStats) Variance() float64 {
+	if s.count < 2 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.count-1)
+}
+
+// PopulationVariance returns the population variance of the values added,
+// dividing by their count rather than their count minus one, or NaN if there
+// are none.
+func (s *RunningStats) PopulationVariance() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.count)
+}
+
+// StdDev returns the sample standard deviation of the values added, or NaN
+// if there are fewer than two.
+func (s *RunningStats) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Min returns the smallest value added, or NaN if there are none.
+func (s *RunningStats) Min() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.min
+}
+
+// Max returns the largest value added, or NaN if there are none.
+func (s *RunningStats) Max() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.max
+}
@@ -0,0 +1,115 @@
+package gobag
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("slice functions", func(t *testing.T) {
+		s := []int{2, 4, 4, 4, 5, 5, 7, 9}
+		require.Equal(t, 40, Sum(s))
+		require.Equal(t, 5.0, Mean(s))
+		require.InDelta(t, 32.0/7, Variance(s), 1e-12)
+		require.InDelta(t, math.Sqrt(32.0/7), StdDev(s), 1e-12)
+
+		m, ok := Min(s)
+		require.True(t, ok)
+		require.Equal(t, 2, m)
+		m, ok = Max(s)
+		require.True(t, ok)
+		require.Equal(t, 9, m)
+
+		require.Equal(t, float32(1.5), Sum([]float32{0.5, 1}))
+	})
+
+	t.Run("empty slices", func(t *testing.T) {
+		require.Zero(t, Sum([]int(nil)))
+		require.True(t, math.IsNaN(Mean([]int{})))
+		require.True(t, math.IsNaN(Variance([]int{1})))
+		_, ok := Min([]float64{})
+		require.False(t, ok)
+		_, ok = Max([]float64{})
+		require.False(t, ok)
+	})
+
+	t.Run("numerically stable", func(t *testing.T) {
+		// A naive sum of squares loses every significant digit here.
+		s := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+		require.InDelta(t, 1e9+10, Mean(s), 1e-6)
+		require.InDelta(t, 30.0, Variance(s), 1e-6)
+
+		large := []int64{math.MaxInt64, math.MaxInt64}
+		require.InDelta(t, float64(math.MaxInt64), Mean(large), 1)
+	})
+
+	t.Run("weighted mean", func(t *testing.T) {
+		mean, err := WeightedMean([]float64{1, 2, 3}, []int{3, 0, 1})
+		require.NoError(t, err)
+		require.Equal(t, 1.5, mean)
+
+		for _, weights := range [][]float64{{1}, {0, 0}, {1, -1}, {1, math.NaN()}} {
+			_, err = WeightedMean([]int{1, 2}, weights)
+			require.ErrorIs(t, err, ErrInvalidWeights, "%v", weights)
+		}
+	})
+}
+
+func TestRunningStats(t *testing.T) {
+	t.Run("matches the slice functions", func(t *testing.T) {
+		var stats RunningStats
+		require.True(t, math.IsNaN(stats.Mean()))
+		require.True(t, math.IsNaN(stats.Min()))
+		require.True(t, math.IsNaN(stats.PopulationVariance()))
+
+		s := []float64{3, -1, 4, 1, 5, 9, 2, 6}
+		for _, v := range s {
+			stats.Add(v)
+		}
+		require.Equal(t, uint64(len(s)), stats.Count())
+		require.Equal(t, Sum(s), stats.Sum())
+		require.InDelta(t, Mean(s), stats.Mean(), 1e-12)
+		require.InDelta(t, Variance(s), stats.Variance(), 1e-12)
+		require.InDelta(t, Variance(s)*7/8, stats.PopulationVariance(), 1e-12)
+		require.InDelta(t, StdDev(s), stats.StdDev(), 1e-12)
+		require.Equal(t, -1.0, stats.Min())
+		require.Equal(t, 9.0, stats.Max())
+	})
+
+	t.Run("merge across goroutines", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		values := make([]float64, 100000)
+		for i := range values {
+			values[i] = r.NormFloat64()*3 + 50
+		}
+
+		shards := make([]RunningStats, 8)
+		var wg sync.WaitGroup
+		for i := range shards {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, v := range values[i*len(values)/8 : (i+1)*len(values)/8] {
+					shards[i].Add(v)
+				}
+			}()
+		}
+		wg.Wait()
+
+		var total RunningStats
+		total.Merge(RunningStats{})
+		for _, shard := range shards {
+			total.Merge(shard)
+		}
+		expected := RunningStatsOf(values)
+		require.Equal(t, expected.Count(), total.Count())
+		require.InDelta(t, expected.Mean(), total.Mean(), 1e-9)
+		require.InDelta(t, expected.Variance(), total.Variance(), 1e-9)
+		require.Equal(t, expected.Min(), total.Min())
+		require.Equal(t, expected.Max(), total.Max())
+	})
+}
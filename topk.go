@@ -0,0 +1,83 @@
+package gobag
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+	"slices"
+)
+
+// TopK returns the k elements of seq with the highest scores, from the highest
+// to the lowest, or nil if seq is empty. Elements with equal scores keep their
+// order in seq. TopK consumes seq in a single pass, keeping the best elements
+// seen so far in a heap, so it runs in O(n log k) time and O(k) memory. It
+// panics if k is negative.
+//
+// Example:
+//
+//	slowest := gobag.TopK(slices.Values(requests), 10, func(r Request) time.Duration { return r.Latency })
+func TopK[T any, S cmp.Ordered](seq iter.Seq[T], k int, score func(T) S) []T {
+	if k < 0 {
+		panic("gobag: negative top k size")
+	}
+	if k == 0 {
+		return nil
+	}
+
+	best := make(scoredHeap[T, S], 0, k)
+	i := 0
+	for element := range seq {
+		candidate := scored[T, S]{element: element, score: score(element), index: i}
+		i++
+		if len(best) < k {
+			heap.Push(&best, candidate)
+		} else if best.less(best[0], candidate) {
+			best[0] = candidate
+			heap.Fix(&best, 0)
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(best, func(a, b scored[T, S]) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.index, b.index)
+	})
+	elements := make([]T, len(best))
+	for i, s := range best {
+		elements[i] = s.element
+	}
+	return elements
+}
+
+type scored[T any, S cmp.Ordered] struct {
+	element T
+	score   S
+	index   int
+}
+
+// scoredHeap is a min-heap whose root is the element ranking lowest: the one
+// with the lowest score, or the latest one among equal scores.
+type scoredHeap[T any, S cmp.Ordered] []scored[T, S]
+
+func (h scoredHeap[T, S]) less(a, b scored[T, S]) bool {
+	if c := cmp.Compare(a.score, b.score); c != 0 {
+		return c < 0
+	}
+	return a.index > b.index
+}
+
+func (h scoredHeap[T, S]) Len() int           { return len(h) }
+func (h scoredHeap[T, S]) Less(i, j int) bool { return h.less(h[i], h[j]) }
+func (h scoredHeap[T, S]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *scoredHeap[T, S]) Push(x any)        { *h = append(*h, x.(scored[T, S])) }
+
+func (h *scoredHeap[T, S]) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,58 @@
+package gobag
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopK(t *testing.T) {
+	t.Run("highest scores first", func(t *testing.T) {
+		words := []string{"go", "gopher", "a", "generics", "iter", "b"}
+		longest := TopK(slices.Values(words), 3, func(s string) int { return len(s) })
+		require.Equal(t, []string{"generics", "gopher", "iter"}, longest)
+	})
+
+	t.Run("ties keep input order", func(t *testing.T) {
+		users := []testUser{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}, {ID: 4, Name: "d"}}
+		constant := func(testUser) int { return 0 }
+		require.Equal(t, users[:2], TopK(slices.Values(users), 2, constant))
+	})
+
+	t.Run("matches sorting", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		values := make([]int, 5000)
+		for i := range values {
+			values[i] = r.IntN(1000)
+		}
+		identity := func(v int) int { return v }
+
+		sorted := slices.SortedStableFunc(slices.Values(values), func(a, b int) int { return cmp.Compare(b, a) })
+		for _, k := range []int{1, 10, 100, 5000, 6000} {
+			require.Equal(t, sorted[:min(k, len(sorted))], TopK(slices.Values(values), k, identity))
+		}
+	})
+
+	t.Run("edge cases", func(t *testing.T) {
+		identity := func(v int) int { return v }
+		require.Nil(t, TopK(slices.Values([]int{1, 2}), 0, identity))
+		require.Nil(t, TopK(slices.Values([]int{}), 3, identity))
+		require.Panics(t, func() { TopK(slices.Values([]int{1}), -1, identity) })
+	})
+}
+
+func BenchmarkTopK(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 1))
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = r.Float64()
+	}
+	identity := func(v float64) float64 { return v }
+	b.ResetTimer()
+	for range b.N {
+		TopK(slices.Values(values), 100, identity)
+	}
+}